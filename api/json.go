@@ -12,24 +12,26 @@ import (
 )
 
 // NewJSON returns a new JSON instance.
-func NewJSON(logger log.Logger, debug bool) *JSON {
+func NewJSON(logger log.Logger, debug bool, opts ...Option) *JSON {
 	if logger == nil {
 		logger = log.NewNopLogger()
 	}
 	return &JSON{
-		logger: logger,
-		debug:  debug,
+		logger:  logger,
+		debug:   debug,
+		options: newOptions(opts),
 	}
 }
 
 // JSON returns a new JSON encoder that logs errors.
 // If debug is set, it sets the stacktrace into the HTTP body responses.
 type JSON struct {
-	logger log.Logger
-	debug  bool
+	logger  log.Logger
+	debug   bool
+	options options
 }
 
-// RenderError renders a HTTPError and logs it if it's a 500.
+// RenderError renders a HTTPError and records it.
 func (j *JSON) RenderError(ctx context.Context, w http.ResponseWriter, httpError HTTPError, e error) {
 	if e == nil {
 		e = errors.New("nil error")
@@ -38,17 +40,19 @@ func (j *JSON) RenderError(ctx context.Context, w http.ResponseWriter, httpError
 	// We log errors and we export them to Sentry.
 	j.RecordError(ctx, httpError, e)
 
+	httpError.Params = j.options.redaction.Map(httpError.Params)
+	httpError.RequestID, _ = toolbox.GetRequestID(ctx)
 	if j.debug {
 		location, _ := toolbox.HasStack(e)
 		j.renderJSON(w, httpError.Status, &DebugHTTPError{
 			HTTPError: httpError,
-			Err:       e.Error(),
+			Err:       j.options.redaction.String(e.Error()),
 			Location:  location,
 		})
 	} else {
 		j.renderJSON(w, httpError.Status, &DebugHTTPError{
 			HTTPError: httpError,
-			Err:       e.Error(),
+			Err:       j.options.redaction.String(e.Error()),
 		})
 	}
 }
@@ -62,18 +66,24 @@ func (j *JSON) Render(ctx context.Context, w http.ResponseWriter, status int, ob
 	}
 }
 
-// RecordError records an error and logs it if it's a 500.
+// RecordError records an error, logging it at the error level if it's a 500 and at the debug level otherwise,
+// or at the warn level in debug mode.
 func (j *JSON) RecordError(ctx context.Context, httpError HTTPError, e error) {
 	if e == nil {
 		e = errors.New("nil error")
 	}
 
-	// We log errors and we export them to Sentry.
+	// We log errors and we export the server ones to Sentry.
+	logger := j.logger
+	if j.options.redaction != nil {
+		logger = toolbox.LoggerWithRedaction(logger, j.options.redaction)
+	}
+	logger = toolbox.LoggerWithRequestContext(ctx, logger)
 	if j.debug || (httpError.Status >= 500 && httpError.Status < 600) {
-		logger := toolbox.LoggerWithRequestContext(ctx, j.logger)
-		logger = toolbox.LoggerWithSentry(ctx, logger)
-		logger.Log("status", httpError.Status, "err", e)
+		logger = toolbox.LoggerWithSentry(ctx, logger, j.options.sentryOptions...)
 	}
+	logger = toolbox.LoggerWithLogFields(ctx, logger)
+	logAt(logger, errorLevel(httpError.Status, j.debug), "status", httpError.Status, "code", httpError.ErrorCode, "err", e)
 }
 
 func (j *JSON) renderJSON(w http.ResponseWriter, status int, object interface{}) {
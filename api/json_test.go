@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+
+	"github.com/solher/toolbox"
+)
+
+// fakeTransport records the events sent to Sentry.
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *fakeTransport) Configure(sentry.ClientOptions) {}
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+func (t *fakeTransport) Flush(time.Duration) bool              { return true }
+func (t *fakeTransport) FlushWithContext(context.Context) bool { return true }
+func (t *fakeTransport) Close()                                {}
+
+func TestJSONRecordErrorReportsOnce(t *testing.T) {
+	transport := &fakeTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.invalid/1", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := sentry.SetHubOnContext(context.Background(), sentry.NewHub(client, sentry.NewScope()))
+	ctx = toolbox.WithErrorReporting(ctx)
+
+	// The logger given to the renderer exports to Sentry too.
+	j := NewJSON(toolbox.LoggerWithSentry(ctx, log.NewNopLogger()), false)
+	e := errors.New("boom")
+	j.RecordError(ctx, HTTPInternal, e)
+	j.RenderError(ctx, httptest.NewRecorder(), HTTPInternal, errors.Wrap(e, "rendering"))
+	toolbox.LoggerWithSentry(ctx, log.NewNopLogger()).Log("err", e)
+
+	if len(transport.events) != 1 {
+		t.Errorf("got %d events, want 1", len(transport.events))
+	}
+}
+
+func TestJSONRecordErrorWithRedaction(t *testing.T) {
+	transport := &fakeTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.invalid/1", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := sentry.SetHubOnContext(context.Background(), sentry.NewHub(client, sentry.NewScope()))
+	ctx = toolbox.WithErrorReporting(ctx)
+
+	// Client errors are only exported by the logger given to the renderer, which sees the redacted errors.
+	j := NewJSON(toolbox.LoggerWithSentry(ctx, log.NewNopLogger()), false, WithRedaction(toolbox.NewRedaction([]string{"token"})))
+	e := toolbox.WithErrNotFound(toolbox.WithKeyValues(errors.New("no user"), "token", "s3cr3t"))
+	j.RecordError(ctx, HTTPNotFound, e)
+	j.RecordError(ctx, HTTPNotFound, e)
+
+	if len(transport.events) != 1 {
+		t.Fatalf("got %d events, want 1", len(transport.events))
+	}
+	if tag := transport.events[0].Tags["error.not_found"]; tag != "true" {
+		t.Errorf("error.not_found tag = %q, want true", tag)
+	}
+	if token := transport.events[0].Extra["token"]; token != toolbox.RedactedValue {
+		t.Errorf("token extra = %v, want it redacted", token)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/solher/toolbox"
+)
+
+// Option configures the JSON and XML renderers.
+type Option func(*options)
+
+type options struct {
+	redaction     *toolbox.Redaction
+	sentryOptions []toolbox.SentryOption
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.sentryOptions = append(o.sentryOptions, toolbox.SentryWithRedaction(o.redaction))
+	return o
+}
+
+// WithRedaction redacts the logged and exported errors, as well as the messages and params of the rendered errors.
+func WithRedaction(redaction *toolbox.Redaction) Option {
+	return func(o *options) {
+		o.redaction = redaction
+	}
+}
+
+// WithSentryOptions configures the export of errors to Sentry, for example their fingerprinting
+// with toolbox.FingerprintByKey("code").
+func WithSentryOptions(opts ...toolbox.SentryOption) Option {
+	return func(o *options) {
+		o.sentryOptions = append(o.sentryOptions, opts...)
+	}
+}
+
+// errorLevel returns the level at which errors rendered with the given status are logged.
+func errorLevel(status int, debug bool) level.Value {
+	switch {
+	case status >= 500 && status < 600:
+		return level.ErrorValue()
+	case debug:
+		return level.WarnValue()
+	default:
+		return level.DebugValue()
+	}
+}
+
+func logAt(logger log.Logger, lvl level.Value, keyvals ...interface{}) error {
+	return log.WithPrefix(logger, level.Key(), lvl).Log(keyvals...)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/pkg/errors"
+
+	"github.com/solher/toolbox"
+)
+
+// ErrorRenderer renders and records errors, as JSON and XML do.
+type ErrorRenderer interface {
+	RenderError(ctx context.Context, w http.ResponseWriter, httpError HTTPError, e error)
+	RecordError(ctx context.Context, httpError HTTPError, e error)
+}
+
+// NewRecovery returns a new Recovery middleware, which recovers from the panics of the next handlers
+// and renders them as HTTPInternal with renderer, which also logs them and exports them to Sentry.
+// If the response was already started, the panic is only recorded.
+// It should be wrapped by the RequestContext middleware so that the errors carry the request context.
+func NewRecovery(renderer ErrorRenderer) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := toolbox.WrapResponseWriter(w)
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// net/http uses this panic to abort responses on purpose.
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				err := newPanicError(recovered)
+				if rw.Status() != 0 {
+					renderer.RecordError(r.Context(), HTTPInternal, err)
+					return
+				}
+				renderer.RenderError(r.Context(), rw, HTTPInternal, err)
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// panicError is a recovered panic carrying the stack of the goroutine where it was raised.
+type panicError struct {
+	recovered any
+	stack     errors.StackTrace
+}
+
+func newPanicError(recovered any) error {
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	// The stack is trimmed to start where the panic was first raised rather than in the deferred function,
+	// or in a deferred function of the next handlers raising it again.
+	start := 0
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			start = i + 1
+		}
+	}
+	pcs = pcs[start:]
+	stack := make(errors.StackTrace, len(pcs))
+	for i, pc := range pcs {
+		stack[i] = errors.Frame(pc)
+	}
+	return &panicError{recovered: recovered, stack: stack}
+}
+
+func (e *panicError) Error() string                 { return fmt.Sprintf("panic: %v", e.recovered) }
+func (e *panicError) StackTrace() errors.StackTrace { return e.stack }
+
+// Unwrap returns the recovered value when it is an error.
+func (e *panicError) Unwrap() error {
+	err, _ := e.recovered.(error)
+	return err
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/solher/toolbox"
+)
+
+// recordingRenderer records the rendered errors.
+type recordingRenderer struct {
+	errs []error
+}
+
+func (r *recordingRenderer) RenderError(ctx context.Context, w http.ResponseWriter, httpError HTTPError, e error) {
+	r.errs = append(r.errs, e)
+	w.WriteHeader(httpError.Status)
+}
+
+func (r *recordingRenderer) RecordError(ctx context.Context, httpError HTTPError, e error) {
+	r.errs = append(r.errs, e)
+}
+
+// panicLine is the line where panickingHandler panics.
+var panicLine int
+
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	_, _, line, _ := runtime.Caller(0)
+	panicLine = line + 2
+	panic("boom")
+}
+
+func TestRecoveryStack(t *testing.T) {
+	repanic := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					panic(recovered)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+	tests := []struct {
+		name       string
+		middleware func(next http.Handler) http.Handler
+	}{
+		{"access log", toolbox.NewAccessLog(log.NewNopLogger())},
+		{"sentry hub", toolbox.NewSentryHub()},
+		{"tracing", toolbox.NewTracing()},
+		{"raised again", repanic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := &recordingRenderer{}
+			handler := NewRecovery(renderer)(tt.middleware(http.HandlerFunc(panickingHandler)))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != http.StatusInternalServerError || len(renderer.errs) != 1 {
+				t.Fatalf("got status %d and errors %v, want a rendered 500", w.Code, renderer.errs)
+			}
+			location, ok := toolbox.HasStack(renderer.errs[0])
+			if want := fmt.Sprintf("recovery_test.go:%d", panicLine); !ok || !strings.HasSuffix(location, want) {
+				t.Errorf("location = %q, want the line of the panicking handler", location)
+			}
+		})
+	}
+}
+
+func TestRecoveryAccessLogStatus(t *testing.T) {
+	var buf bytes.Buffer
+	handler := toolbox.NewAccessLog(log.NewLogfmtLogger(&buf))(NewRecovery(&recordingRenderer{})(http.HandlerFunc(panickingHandler)))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(buf.String(), "status=500") {
+		t.Errorf("access log %q does not contain status=500", buf.String())
+	}
+}
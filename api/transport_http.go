@@ -10,6 +10,8 @@ type HTTPError struct {
 	ErrorCode string `json:"errorCode"`
 	// Additional infos.
 	Params map[string]interface{} `json:"params,omitempty"`
+	// The ID of the request, to correlate the error with the logs.
+	RequestID string `json:"requestId,omitempty"`
 }
 
 // DebugHTTPError defines a standard format for HTTP errors with additional debug info.
@@ -12,47 +12,47 @@ import (
 )
 
 // NewXML returns a new XML instance.
-func NewXML(logger log.Logger, debug bool) *XML {
+func NewXML(logger log.Logger, debug bool, opts ...Option) *XML {
 	if logger == nil {
 		logger = log.NewNopLogger()
 	}
 	return &XML{
-		logger: logger,
-		debug:  debug,
+		logger:  logger,
+		debug:   debug,
+		options: newOptions(opts),
 	}
 }
 
 // XML returns a new XML encoder that logs errors.
 // If debug is set, it sets the stacktrace into the HTTP body responses.
 type XML struct {
-	logger log.Logger
-	debug  bool
+	logger  log.Logger
+	debug   bool
+	options options
 }
 
-// RenderError renders a HTTPError and logs it if it's a 500.
+// RenderError renders a HTTPError and records it.
 func (x *XML) RenderError(ctx context.Context, w http.ResponseWriter, httpError HTTPError, e error) {
 	if e == nil {
 		e = errors.New("null")
 	}
 
 	// We log errors and we export them to Sentry.
-	if x.debug || (httpError.Status >= 500 && httpError.Status < 600) {
-		logger := toolbox.LoggerWithRequestContext(ctx, x.logger)
-		logger = toolbox.LoggerWithSentry(ctx, logger)
-		logger.Log("status", httpError.Status, "err", e)
-	}
+	x.RecordError(ctx, httpError, e)
 
+	httpError.Params = x.options.redaction.Map(httpError.Params)
+	httpError.RequestID, _ = toolbox.GetRequestID(ctx)
 	if x.debug {
 		location, _ := toolbox.HasStack(e)
 		x.renderXML(w, httpError.Status, &DebugHTTPError{
 			HTTPError: httpError,
-			Err:       e.Error(),
+			Err:       x.options.redaction.String(e.Error()),
 			Location:  location,
 		})
 	} else {
 		x.renderXML(w, httpError.Status, &DebugHTTPError{
 			HTTPError: httpError,
-			Err:       e.Error(),
+			Err:       x.options.redaction.String(e.Error()),
 		})
 	}
 }
@@ -66,6 +66,26 @@ func (x *XML) Render(ctx context.Context, w http.ResponseWriter, status int, obj
 	}
 }
 
+// RecordError records an error, logging it at the error level if it's a 500 and at the debug level otherwise,
+// or at the warn level in debug mode.
+func (x *XML) RecordError(ctx context.Context, httpError HTTPError, e error) {
+	if e == nil {
+		e = errors.New("nil error")
+	}
+
+	// We log errors and we export the server ones to Sentry.
+	logger := x.logger
+	if x.options.redaction != nil {
+		logger = toolbox.LoggerWithRedaction(logger, x.options.redaction)
+	}
+	logger = toolbox.LoggerWithRequestContext(ctx, logger)
+	if x.debug || (httpError.Status >= 500 && httpError.Status < 600) {
+		logger = toolbox.LoggerWithSentry(ctx, logger, x.options.sentryOptions...)
+	}
+	logger = toolbox.LoggerWithLogFields(ctx, logger)
+	logAt(logger, errorLevel(httpError.Status, x.debug), "status", httpError.Status, "code", httpError.ErrorCode, "err", e)
+}
+
 func (x *XML) renderXML(w http.ResponseWriter, status int, object interface{}) {
 	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
 	w.WriteHeader(status)
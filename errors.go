@@ -2,12 +2,20 @@ package toolbox
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/go-kit/log"
 	"github.com/pkg/errors"
 )
 
+// messageError is an error without stack, made from a logged string.
+type messageError string
+
+func (e messageError) Error() string { return string(e) }
+
 type causer interface {
 	Cause() error
 }
@@ -78,12 +86,77 @@ func WithKeyValues(err error, keyvals ...interface{}) error {
 	}
 }
 
-// HasKeyValues returns embedded key values from the error.
+// DuplicateKeys defines which value is kept when several layers of an error chain embed the same key.
+// Except with DuplicateKeysAll, a key repeated within a layer only keeps its first value too.
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysOuter keeps the value of the outermost layer, which is the closest to where the error was handled.
+	DuplicateKeysOuter DuplicateKeys = iota
+	// DuplicateKeysInner keeps the value of the innermost layer, which is the closest to where the error was thrown.
+	DuplicateKeysInner
+	// DuplicateKeysAll keeps every value.
+	DuplicateKeysAll
+)
+
+// HasKeyValues returns the key values embedded in every layer of the error,
+// keeping the value of the outermost layer for duplicate keys.
 func HasKeyValues(err error) (keyvals []interface{}, ok bool) {
-	if foundErr := findBehavior(err, func(err error) bool { _, ok := err.(keyValuer); return ok }); foundErr != nil {
-		return foundErr.(keyValuer).KeyValues(), true
+	return HasKeyValuesWithPolicy(err, DuplicateKeysOuter)
+}
+
+// HasKeyValuesWithPolicy returns the key values embedded in every layer of the error,
+// from the outermost to the innermost one, handling duplicate keys with policy.
+func HasKeyValuesWithPolicy(err error, policy DuplicateKeys) (keyvals []interface{}, ok bool) {
+	layers := keyValueLayers(err)
+	if len(layers) == 0 {
+		return nil, false
+	}
+	if policy == DuplicateKeysInner {
+		slices.Reverse(layers)
+	}
+	seen := make(map[interface{}]struct{})
+	for _, layer := range layers {
+		for i := 0; i < len(layer); i += 2 {
+			if policy != DuplicateKeysAll {
+				// Keys that are not comparable cannot be deduplicated.
+				if k := layer[i]; k != nil && reflect.TypeOf(k).Comparable() {
+					if _, ok := seen[k]; ok {
+						continue
+					}
+					seen[k] = struct{}{}
+				}
+			}
+			keyvals = append(keyvals, layer[i], layer[i+1])
+		}
 	}
-	return nil, false
+	return keyvals, true
+}
+
+// keyValueLayers returns the key values of every layer of the error, from the outermost to the innermost one,
+// each layer being of even length.
+func keyValueLayers(err error) (layers [][]interface{}) {
+	for err != nil {
+		foundErr := findBehavior(err, func(err error) bool { _, ok := err.(keyValuer); return ok })
+		if foundErr == nil {
+			break
+		}
+		// The layers under a redacted error are only reachable redacted.
+		if redacted, ok := foundErr.(*redactedChain); ok {
+			return append(layers, redacted.layers...)
+		}
+		layer := foundErr.(keyValuer).KeyValues()
+		if len(layer)%2 != 0 {
+			layer = append(slices.Clip(layer), log.ErrMissingValue)
+		}
+		layers = append(layers, layer)
+		cause, ok := foundErr.(causer)
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return layers
 }
 
 type errNotFound interface {
@@ -172,3 +245,51 @@ func IsErrRetriable(err error) bool {
 	}
 	return false
 }
+
+type errReported interface {
+	IsErrReported()
+}
+
+type errReportedBehavior struct{}
+
+func (err *errReportedBehavior) IsErrReported() {}
+
+// WithErrReported wraps an error with a behavior indicating that it was already exported to Sentry.
+func WithErrReported(err error) error {
+	return struct {
+		error
+		*causerBehavior
+		*errReportedBehavior
+	}{
+		err,
+		&causerBehavior{cause: err},
+		&errReportedBehavior{},
+	}
+}
+
+// IsErrReported indicates if an error was already exported to Sentry.
+func IsErrReported(err error) bool {
+	if foundErr := findBehavior(err, func(err error) bool { _, ok := err.(errReported); return ok }); foundErr != nil {
+		return true
+	}
+	return false
+}
+
+// nextCause returns the error wrapped by err, following both Cause and Unwrap, or nil.
+func nextCause(err error) error {
+	if cause, ok := err.(causer); ok {
+		return cause.Cause()
+	}
+	return errors.Unwrap(err)
+}
+
+// rootCause returns the innermost cause of an error, following both Cause and Unwrap.
+func rootCause(err error) error {
+	for {
+		next := nextCause(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
@@ -0,0 +1,75 @@
+package toolbox
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+)
+
+func TestHasKeyValuesWithPolicy(t *testing.T) {
+	inner := WithKeyValues(errors.New("boom"), "user", "alice", "attempt", 1)
+	middle := WithErrNotFound(WithKeyValues(inner, "user", "bob", "user", "carol"))
+	outer := WithKeyValues(errors.Wrap(middle, "handling"), "route", "/users", "user", "dave")
+
+	tests := []struct {
+		name   string
+		policy DuplicateKeys
+		want   []interface{}
+	}{
+		{"outer", DuplicateKeysOuter, []interface{}{"route", "/users", "user", "dave", "attempt", 1}},
+		{"inner", DuplicateKeysInner, []interface{}{"user", "alice", "attempt", 1, "route", "/users"}},
+		{"all", DuplicateKeysAll, []interface{}{"route", "/users", "user", "dave", "user", "bob", "user", "carol", "user", "alice", "attempt", 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := HasKeyValuesWithPolicy(outer, tt.policy)
+			if !ok || !slices.Equal(got, tt.want) {
+				t.Errorf("key values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasKeyValuesRepeatedKeyWithinALayer(t *testing.T) {
+	err := WithKeyValues(errors.New("boom"), "user", "bob", "user", "carol")
+	if got, _ := HasKeyValuesWithPolicy(err, DuplicateKeysOuter); !slices.Equal(got, []interface{}{"user", "bob"}) {
+		t.Errorf("outer key values = %v, want the first value", got)
+	}
+	if got, _ := HasKeyValuesWithPolicy(err, DuplicateKeysInner); !slices.Equal(got, []interface{}{"user", "bob"}) {
+		t.Errorf("inner key values = %v, want the first value", got)
+	}
+}
+
+func TestHasKeyValuesOddLayers(t *testing.T) {
+	keyvals := make([]interface{}, 1, 2)
+	keyvals[0] = "orphan"
+	err := WithKeyValues(WithKeyValues(errors.New("boom"), keyvals...), "user", "alice", "dangling")
+
+	got, ok := HasKeyValuesWithPolicy(err, DuplicateKeysAll)
+	want := []interface{}{"user", "alice", "dangling", log.ErrMissingValue, "orphan", log.ErrMissingValue}
+	if !ok || !slices.Equal(got, want) {
+		t.Errorf("key values = %v, want %v", got, want)
+	}
+	if extra := keyvals[:2][1]; extra != nil {
+		t.Errorf("the key values of the error were written: %v", extra)
+	}
+}
+
+func TestHasKeyValuesUncomparableKeys(t *testing.T) {
+	key := []string{"a"}
+	err := WithKeyValues(WithKeyValues(errors.New("boom"), key, 1), key, 2)
+	if got, _ := HasKeyValues(err); len(got) != 4 {
+		t.Errorf("key values = %v, want both values of the uncomparable key", got)
+	}
+}
+
+func TestHasKeyValuesWithout(t *testing.T) {
+	if got, ok := HasKeyValues(errors.New("boom")); ok || got != nil {
+		t.Errorf("key values = %v, %v, want none", got, ok)
+	}
+	if _, ok := HasKeyValues(nil); ok {
+		t.Error("found key values on a nil error")
+	}
+}
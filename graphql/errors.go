@@ -3,8 +3,10 @@ package graphql
 import (
 	"context"
 	"errors"
+	"maps"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/solher/toolbox"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
@@ -66,12 +68,25 @@ func NewErrorGenerator(logger log.Logger, debug bool) ErrorGenerator {
 		if e == nil {
 			e = errors.New("null")
 		}
+		// The extensions of the predefined errors are shared, so we work on a copy.
+		gqlErr.Extensions = maps.Clone(gqlErr.Extensions)
 
-		if gqlErrCode := gqlErr.Extensions["errorCode"].(string); debug || gqlErrCode == "INTERNAL_ERROR" || gqlErrCode == "SERVICE_UNAVAILABLE" {
-			toolbox.LoggerWithRequestContext(ctx, logger).Log("code", gqlErrCode, "err", e)
+		// Server errors are logged at the error level, the other ones at the debug level, or at the warn level in debug mode.
+		gqlErrCode := gqlErr.Extensions["errorCode"].(string)
+		lvl := level.DebugValue()
+		switch {
+		case gqlErrCode == "INTERNAL_ERROR" || gqlErrCode == "SERVICE_UNAVAILABLE":
+			lvl = level.ErrorValue()
+		case debug:
+			lvl = level.WarnValue()
 		}
+		log.WithPrefix(toolbox.LoggerWithLogFields(ctx, toolbox.LoggerWithRequestContext(ctx, logger)), level.Key(), lvl).Log("code", gqlErrCode, "err", e)
 
+		gqlErr.Err = e
 		gqlErr.Extensions["err"] = e.Error()
+		if id, err := toolbox.GetRequestID(ctx); err == nil {
+			gqlErr.Extensions["requestId"] = id
+		}
 		if debug {
 			location, _ := toolbox.HasStack(e)
 			gqlErr.Extensions["location"] = location
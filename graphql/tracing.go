@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/solher/toolbox"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the package.
+const tracerName = toolbox.TracerName + "/graphql"
+
+// Tracing is a gqlgen extension creating OpenTelemetry spans for the operations and for the fields
+// with a resolver, with the global tracer provider.
+//
+//	srv.Use(graphql.Tracing{Redaction: redaction})
+type Tracing struct {
+	// Redaction redacts the errors recorded on the spans.
+	Redaction *toolbox.Redaction
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Tracing{}
+
+// ExtensionName implements the graphql.HandlerExtension interface.
+func (Tracing) ExtensionName() string {
+	return "OpenTelemetryTracing"
+}
+
+// Validate implements the graphql.HandlerExtension interface.
+func (Tracing) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse implements the graphql.ResponseInterceptor interface.
+func (t Tracing) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	opCtx := graphql.GetOperationContext(ctx)
+	name, attrs := "graphql.operation", []attribute.KeyValue{}
+	if opCtx.Operation != nil {
+		operationType := string(opCtx.Operation.Operation)
+		name = operationType
+		attrs = append(attrs, semconv.GraphQLOperationTypeKey.String(operationType))
+		if opCtx.OperationName != "" {
+			name += " " + opCtx.OperationName
+			attrs = append(attrs, semconv.GraphQLOperationName(opCtx.OperationName))
+		}
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, t.Redaction.String(resp.Errors.Error()))
+	}
+	return resp
+}
+
+// InterceptField implements the graphql.FieldInterceptor interface.
+func (t Tracing) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(attribute.String("graphql.field.path", fc.Path().String())),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	// The errors generated by ErrorGenerator keep the original error, which carries the behaviors.
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Err != nil {
+		toolbox.RecordSpanError(span, gqlErr.Err, t.Redaction)
+	} else {
+		toolbox.RecordSpanError(span, err, t.Redaction)
+	}
+	return res, err
+}
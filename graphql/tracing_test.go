@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	"github.com/solher/toolbox"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newSpanExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func fieldContext(ctx context.Context, isResolver bool) context.Context {
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object:     "Query",
+		Field:      graphql.CollectedField{Field: &ast.Field{Name: "user", Alias: "user"}},
+		IsResolver: isResolver,
+	})
+}
+
+func TestTracingResolverSpans(t *testing.T) {
+	exporter := newSpanExporter(t)
+	tracing := Tracing{Redaction: toolbox.NewRedaction([]string{"token"}, regexp.MustCompile(`s3cr3t`))}
+	generate := NewErrorGenerator(nil, false)
+	ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+		Operation:     &ast.OperationDefinition{Operation: ast.Query},
+		OperationName: "GetUser",
+	})
+
+	resp := tracing.InterceptResponse(ctx, func(ctx context.Context) *graphql.Response {
+		// Fields without a resolver have no span.
+		tracing.InterceptField(fieldContext(ctx, false), func(ctx context.Context) (any, error) { return "alice", nil })
+		_, err := tracing.InterceptField(fieldContext(ctx, true), func(ctx context.Context) (any, error) {
+			err := toolbox.WithErrNotFound(toolbox.WithKeyValues(errors.New("no user for token s3cr3t"), "token", "s3cr3t"))
+			return nil, generate(ctx, ErrNotFound, err)
+		})
+		var gqlErr *gqlerror.Error
+		errors.As(err, &gqlErr)
+		return &graphql.Response{Errors: gqlerror.List{gqlErr}}
+	})
+	if resp == nil {
+		t.Fatal("InterceptResponse returned no response")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the operation and the resolver", len(spans))
+	}
+	resolver, operation := spans[0], spans[1]
+	if operation.Name != "query GetUser" || operation.Status.Code != codes.Error {
+		t.Errorf("operation span = %q with status %v, want query GetUser with an error", operation.Name, operation.Status.Code)
+	}
+	if resolver.Name != "Query.user" || resolver.Parent.SpanID() != operation.SpanContext.SpanID() {
+		t.Errorf("resolver span = %q, want Query.user as a child of the operation", resolver.Name)
+	}
+	if resolver.Status.Code != codes.Error || strings.Contains(resolver.Status.Description, "s3cr3t") {
+		t.Errorf("resolver span status = %+v, want a redacted error", resolver.Status)
+	}
+	if len(resolver.Events) != 1 {
+		t.Fatalf("got %d resolver span events, want the recorded error", len(resolver.Events))
+	}
+	attrs := map[attribute.Key]string{}
+	for _, attr := range resolver.Events[0].Attributes {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	if attrs["error.not_found"] != "true" || attrs["error.token"] != toolbox.RedactedValue {
+		t.Errorf("resolver error attributes = %v, want the behaviors and the redacted key values", attrs)
+	}
+}
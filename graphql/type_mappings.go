@@ -1,8 +1,13 @@
 package graphql
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"regexp"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -27,17 +32,80 @@ func UnmarshalString(v any) (string, error) {
 	return s, nil
 }
 
+// MarshalUUID serializes the UUID as a string.
+func MarshalUUID(v types.UUID) graphql.Marshaler {
+	return graphql.MarshalString(v.String())
+}
+
+// UnmarshalUUID accepts a UUID string.
+func UnmarshalUUID(v any) (types.UUID, error) {
+	if s, ok := v.(string); ok {
+		return types.ParseUUID(s)
+	}
+	return types.UUID{}, errors.New("uuid must be a valid UUID string")
+}
+
+// MarshalID serializes a prefixed ID as a string.
+// GqlGen cannot bind generic functions, so it is meant to be wrapped per ID kind.
+func MarshalID[K types.IDKind](v types.ID[K]) graphql.Marshaler {
+	return graphql.MarshalID(v.String())
+}
+
+// UnmarshalID accepts a prefixed ID string, ensuring its prefix matches its kind.
+func UnmarshalID[K types.IDKind](v any) (types.ID[K], error) {
+	if s, ok := v.(string); ok {
+		return types.ParseID[K](s)
+	}
+	return types.ID[K]{}, errors.New("id must be a valid prefixed ID string")
+}
+
+// ToGlobalID returns a Relay global ID, the base64 encoding of "typeName:id".
+func ToGlobalID(typeName, id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + id))
+}
+
+// FromGlobalID decodes a Relay global ID.
+func FromGlobalID(globalID string) (typeName, id string, err error) {
+	decoded, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", "", errors.New("global id must be base64 encoded")
+	}
+	typeName, id, ok := strings.Cut(string(decoded), ":")
+	if !ok || typeName == "" || id == "" {
+		return "", "", errors.New("global id must encode 'typeName:id'")
+	}
+	return typeName, id, nil
+}
+
+// MarshalGlobalID serializes a prefixed ID as a Relay global ID of the given type.
+func MarshalGlobalID[K types.IDKind](typeName string, v types.ID[K]) graphql.Marshaler {
+	return graphql.MarshalID(ToGlobalID(typeName, v.String()))
+}
+
+// UnmarshalGlobalID accepts a Relay global ID of the given type wrapping a prefixed ID.
+func UnmarshalGlobalID[K types.IDKind](typeName string, v any) (types.ID[K], error) {
+	if s, ok := v.(string); ok {
+		gotTypeName, id, err := FromGlobalID(s)
+		if err != nil {
+			return types.ID[K]{}, err
+		}
+		if gotTypeName != typeName {
+			return types.ID[K]{}, fmt.Errorf("global id must be a %s id, got a %s id", typeName, gotTypeName)
+		}
+		return types.ParseID[K](id)
+	}
+	return types.ID[K]{}, errors.New("id must be a valid global ID string")
+}
+
 // MarshalTime serializes the time as a HH:MM:SS string.
 func MarshalTime(v types.Time) graphql.Marshaler {
 	return graphql.MarshalString(v.String())
 }
 
-// UnmarshalTime accepts a 'HH:MM:SS' formatted string.
+// UnmarshalTime accepts a 'HH:MM:SS[.fffffffff]' formatted string, whatever the parse mode of the types package.
 func UnmarshalTime(v any) (types.Time, error) {
 	if s, ok := v.(string); ok {
-		if parsed, err := time.ParseInLocation(time.TimeOnly, s, time.UTC); err == nil {
-			return types.Time{Time: parsed}, nil
-		}
+		return types.ParseTime(s, types.ParseStrict)
 	}
 	return types.Time{}, errors.New("time must be 'HH:MM:SS' formatted string")
 }
@@ -47,12 +115,10 @@ func MarshalDate(v types.Date) graphql.Marshaler {
 	return graphql.MarshalString(v.String())
 }
 
-// UnmarshalDate accepts a 'YYYY-MM-DD' formatted string.
+// UnmarshalDate accepts a 'YYYY-MM-DD' formatted string, whatever the parse mode of the types package.
 func UnmarshalDate(v any) (types.Date, error) {
 	if s, ok := v.(string); ok {
-		if parsed, err := time.ParseInLocation(time.DateOnly, s, time.UTC); err == nil {
-			return types.Date{Time: parsed}, nil
-		}
+		return types.ParseDate(s, types.ParseStrict)
 	}
 	return types.Date{}, errors.New("date must be 'YYYY-MM-DD' formatted string")
 }
@@ -87,36 +153,132 @@ func UnmarshalDateTime(v any) (time.Time, error) {
 	return time.Time{}, errors.New("datetime must be a valid RFC3339 formatted string")
 }
 
-var countryRegex = regexp.MustCompile("^[A-Z]{2}$")
+// MarshalRRule serializes the recurrence rule as an RFC 5545 RRULE string.
+func MarshalRRule(v types.RRule) graphql.Marshaler {
+	return graphql.MarshalString(v.String())
+}
+
+// UnmarshalRRule accepts an RFC 5545 RRULE string.
+func UnmarshalRRule(v any) (types.RRule, error) {
+	if s, ok := v.(string); ok {
+		return types.ParseRRule(s)
+	}
+	return types.RRule{}, errors.New("recurrence rule must be a valid RFC 5545 RRULE string")
+}
+
+// MarshalPoint serializes the point as a GeoJSON Point.
+func MarshalPoint(v types.Point) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		b, _ := json.Marshal(v)
+		w.Write(b)
+	})
+}
+
+// UnmarshalPoint accepts either a GeoJSON Point or an object with lat and lng fields.
+func UnmarshalPoint(v any) (types.Point, error) {
+	if m, ok := v.(map[string]any); ok {
+		if coordinates, ok := m["coordinates"].([]any); ok && m["type"] == "Point" && len(coordinates) == 2 {
+			lng, lngErr := graphql.UnmarshalFloat(coordinates[0])
+			lat, latErr := graphql.UnmarshalFloat(coordinates[1])
+			if lngErr == nil && latErr == nil {
+				return types.NewPoint(lat, lng), nil
+			}
+		}
+		lat, latErr := graphql.UnmarshalFloat(m["lat"])
+		lng, lngErr := graphql.UnmarshalFloat(m["lng"])
+		if latErr == nil && lngErr == nil {
+			return types.NewPoint(lat, lng), nil
+		}
+	}
+	return types.Point{}, errors.New("point must be a GeoJSON Point or an object with lat and lng fields")
+}
 
 // MarshalCountry serializes the country as an ISO 3166-1 alpha-2 code.
-func MarshalCountry(v string) graphql.Marshaler {
-	return graphql.MarshalString(v)
+func MarshalCountry(v types.Country) graphql.Marshaler {
+	return graphql.MarshalString(v.String())
 }
 
 // UnmarshalCountry ensures the country is an ISO 3166-1 alpha-2 code.
-func UnmarshalCountry(v any) (string, error) {
+func UnmarshalCountry(v any) (types.Country, error) {
 	if s, ok := v.(string); ok {
-		if countryRegex.MatchString(s) {
-			return s, nil
-		}
+		return types.ParseCountry(s)
 	}
 	return "", errors.New("country must be a valid ISO 3166-1 alpha-2 code")
 }
 
-var languageRegex = regexp.MustCompile("^[a-z]{2}(-[0-9A-Z]+)?$")
+// MarshalMoney serializes the money as an object with a string amount and an ISO 4217 currency code.
+func MarshalMoney(v types.Money) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		b, _ := json.Marshal(v)
+		w.Write(b)
+	})
+}
 
-// MarshalLanguage serializes the language as an IETF language tag.
-func MarshalLanguage(v string) graphql.Marshaler {
-	return graphql.MarshalString(v)
+// UnmarshalMoney accepts an object with a string amount and an ISO 4217 currency code.
+func UnmarshalMoney(v any) (types.Money, error) {
+	if m, ok := v.(map[string]any); ok {
+		currency, _ := m["currency"].(string)
+		var amount string
+		switch a := m["amount"].(type) {
+		case string:
+			amount = a
+		case json.Number:
+			amount = a.String()
+		case int64:
+			amount = strconv.FormatInt(a, 10)
+		case float64:
+			amount = strconv.FormatFloat(a, 'f', -1, 64)
+		}
+		return types.ParseMoney(amount, currency)
+	}
+	return types.Money{}, errors.New("money must be an object with an amount and an ISO 4217 currency code")
 }
 
-// UnmarshalLanguage ensures the language is an IETF language tag.
-func UnmarshalLanguage(v any) (string, error) {
+// MarshalLanguage serializes the language as a BCP 47 language tag.
+func MarshalLanguage(v types.Language) graphql.Marshaler {
+	return graphql.MarshalString(v.String())
+}
+
+// UnmarshalLanguage ensures the language is a BCP 47 language tag and canonicalizes it.
+func UnmarshalLanguage(v any) (types.Language, error) {
 	if s, ok := v.(string); ok {
-		if languageRegex.MatchString(s) {
-			return s, nil
-		}
+		return types.ParseLanguage(s)
 	}
 	return "", errors.New("language must be a valid IETF language tag")
 }
+
+// MarshalJSON serializes the raw JSON value as is.
+func MarshalJSON(v types.JSONRaw) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		b, _ := v.MarshalJSON()
+		w.Write(b)
+	})
+}
+
+// UnmarshalJSON accepts any JSON value.
+func UnmarshalJSON(v any) (types.JSONRaw, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.New("json must be a valid JSON value")
+	}
+	return types.JSONRaw(b), nil
+}
+
+// MarshalMap serializes the raw JSON object as is.
+func MarshalMap(v types.JSONRaw) graphql.Marshaler {
+	return MarshalJSON(v)
+}
+
+// UnmarshalMap accepts a JSON object.
+func UnmarshalMap(v any) (types.JSONRaw, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if _, ok := v.(map[string]any); ok {
+		return UnmarshalJSON(v)
+	}
+	return nil, errors.New("map must be a JSON object")
+}
@@ -0,0 +1,132 @@
+package toolbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// LogDebug logs keyvals at the debug level.
+func LogDebug(logger log.Logger, keyvals ...interface{}) error {
+	return level.Debug(logger).Log(keyvals...)
+}
+
+// LogInfo logs keyvals at the info level.
+func LogInfo(logger log.Logger, keyvals ...interface{}) error {
+	return level.Info(logger).Log(keyvals...)
+}
+
+// LogWarn logs keyvals at the warn level.
+func LogWarn(logger log.Logger, keyvals ...interface{}) error {
+	return level.Warn(logger).Log(keyvals...)
+}
+
+// LogError logs keyvals at the error level.
+func LogError(logger log.Logger, keyvals ...interface{}) error {
+	return level.Error(logger).Log(keyvals...)
+}
+
+// levels orders the levels by severity.
+var levels = []level.Value{level.DebugValue(), level.InfoValue(), level.WarnValue(), level.ErrorValue()}
+
+func levelSeverity(lvl string) (int, bool) {
+	for i, v := range levels {
+		if v.String() == lvl {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// DynamicLevel is a minimum log level that can be changed at runtime, for example through its HTTP handler.
+type DynamicLevel struct {
+	severity atomic.Int32
+}
+
+// NewDynamicLevel returns a DynamicLevel set to lvl.
+func NewDynamicLevel(lvl level.Value) *DynamicLevel {
+	l := &DynamicLevel{}
+	l.Set(lvl)
+	return l
+}
+
+// Get returns the current level.
+func (l *DynamicLevel) Get() level.Value {
+	return levels[l.severity.Load()]
+}
+
+// Set changes the level.
+func (l *DynamicLevel) Set(lvl level.Value) {
+	severity, _ := levelSeverity(lvl.String())
+	l.severity.Store(int32(severity))
+}
+
+// SetString changes the level from its name, such as "warn".
+func (l *DynamicLevel) SetString(lvl string) error {
+	severity, ok := levelSeverity(lvl)
+	if !ok {
+		return fmt.Errorf("unknown level %q", lvl)
+	}
+	l.severity.Store(int32(severity))
+	return nil
+}
+
+type dynamicLevelPayload struct {
+	Level string `json:"level"`
+}
+
+// ServeHTTP returns the current level on GET and changes it on PUT, with a body such as {"level":"debug"}.
+// It must only be exposed on an admin endpoint.
+func (l *DynamicLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload dynamicLevelPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "could not decode the JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := l.SetString(payload.Level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(dynamicLevelPayload{Level: l.Get().String()})
+}
+
+// LoggerWithLevel wraps next and drops the log lines below the level. Lines without level count as info.
+func LoggerWithLevel(next log.Logger, lvl *DynamicLevel) log.Logger {
+	return &levelLogger{
+		next:  next,
+		level: lvl,
+	}
+}
+
+type levelLogger struct {
+	next  log.Logger
+	level *DynamicLevel
+}
+
+func (l *levelLogger) Log(keyvals ...interface{}) error {
+	severity, _ := levelSeverity(level.InfoValue().String())
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == level.Key() {
+			if s, ok := levelSeverity(fmt.Sprint(keyvals[i+1])); ok {
+				severity = s
+			}
+		}
+	}
+	if int32(severity) < l.level.severity.Load() {
+		return nil
+	}
+	return l.next.Log(keyvals...)
+}
@@ -2,12 +2,44 @@ package toolbox
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/go-kit/log"
-	"github.com/pkg/errors"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrorKeys are the keys of the log line values holding errors, which the toolbox loggers enrich and export.
+// Dotted keys whose last segment is one of them, such as the "request.err" key of an error logged
+// in a slog group, hold errors too.
+var ErrorKeys = []string{"err", "error"}
+
+func isErrorKey(key interface{}) bool {
+	k, ok := key.(string)
+	if !ok {
+		return false
+	}
+	if slices.Contains(ErrorKeys, k) {
+		return true
+	}
+	i := strings.LastIndexByte(k, '.')
+	return i >= 0 && slices.Contains(ErrorKeys, k[i+1:])
+}
+
+// evenKeyValues returns keyvals with a missing value appended if its length is odd,
+// so that values can be appended to it safely.
+func evenKeyValues(keyvals []interface{}) []interface{} {
+	if len(keyvals)%2 != 0 {
+		return append(slices.Clip(keyvals), log.ErrMissingValue)
+	}
+	return keyvals
+}
+
 // LoggerWithStack wraps next and adds stacktrace to log entries when available.
 func LoggerWithStack(next log.Logger) log.Logger {
 	return &stackLogger{
@@ -20,11 +52,13 @@ type stackLogger struct {
 }
 
 func (l *stackLogger) Log(keyvals ...interface{}) error {
-	for i := 0; i < len(keyvals); i += 2 {
-		if keyvals[i] == "err" {
+	keyvals = evenKeyValues(keyvals)
+	n := len(keyvals)
+	for i := 0; i < n; i += 2 {
+		if isErrorKey(keyvals[i]) {
 			if err, ok := keyvals[i+1].(error); ok {
 				if location, ok := HasStack(err); ok {
-					keyvals = append(keyvals, "location", location)
+					keyvals = append(slices.Clip(keyvals), "location", location)
 				}
 			}
 		}
@@ -32,7 +66,8 @@ func (l *stackLogger) Log(keyvals ...interface{}) error {
 	return l.next.Log(keyvals...)
 }
 
-// LoggerWithRequestContext wraps next and adds key values to log entries when available.
+// LoggerWithRequestContext wraps next and adds the request context, as well as the trace and span IDs
+// of the OpenTelemetry span of ctx, to log entries when available.
 func LoggerWithRequestContext(ctx context.Context, next log.Logger) log.Logger {
 	return &reqContextLogger{
 		ctx:  ctx,
@@ -46,29 +81,97 @@ type reqContextLogger struct {
 }
 
 func (l *reqContextLogger) Log(keyvals ...interface{}) error {
-	if method, path, err := GetRequestContext(l.ctx); err == nil {
-		keyvals = append([]interface{}{"method", method, "path", path}, keyvals...)
+	if info, err := GetRequestInfo(l.ctx); err == nil {
+		reqKeyvals := []interface{}{"method", info.Method, "path", info.Path}
+		if info.Route != "" {
+			reqKeyvals = append(reqKeyvals, "route", info.Route)
+		}
+		reqKeyvals = append(reqKeyvals, "remote_ip", info.RemoteIP, "user_agent", info.UserAgent)
+		if info.Subject != "" {
+			reqKeyvals = append(reqKeyvals, "subject", info.Subject)
+		}
+		keyvals = append(reqKeyvals, keyvals...)
+	}
+	if spanContext := trace.SpanContextFromContext(l.ctx); spanContext.IsValid() {
+		keyvals = append([]interface{}{"trace_id", spanContext.TraceID().String(), "span_id", spanContext.SpanID().String()}, keyvals...)
+	}
+	if id, err := GetRequestID(l.ctx); err == nil {
+		keyvals = append([]interface{}{"request_id", id}, keyvals...)
 	}
 	return l.next.Log(keyvals...)
 }
 
-// LoggerWithKeyValues wraps next and adds key values to log entries when available.
-func LoggerWithKeyValues(next log.Logger) log.Logger {
-	return &keyvalsLogger{
+const (
+	ctxLogger    key = "toolbox_logger"
+	ctxLogFields key = "toolbox_log_fields"
+)
+
+// WithLogger returns a context carrying a logger, returned by Logger.
+func WithLogger(ctx context.Context, logger log.Logger) context.Context {
+	return context.WithValue(ctx, ctxLogger, logger)
+}
+
+// WithLogFields returns a context carrying key values added to the log lines of Logger and LoggerWithLogFields,
+// such as a tenant or a job ID. They are added to the ones already in ctx.
+func WithLogFields(ctx context.Context, keyvals ...interface{}) context.Context {
+	keyvals = evenKeyValues(keyvals)
+	fields, _ := ctx.Value(ctxLogFields).([]interface{})
+	return context.WithValue(ctx, ctxLogFields, append(slices.Clip(fields), keyvals...))
+}
+
+// Logger returns the logger of a context with its request context and log fields, or a nop logger if it has none.
+func Logger(ctx context.Context) log.Logger {
+	logger, ok := ctx.Value(ctxLogger).(log.Logger)
+	if !ok {
+		logger = log.NewNopLogger()
+	}
+	return LoggerWithLogFields(ctx, LoggerWithRequestContext(ctx, logger))
+}
+
+// LoggerWithLogFields wraps next and adds the log fields of a context.
+func LoggerWithLogFields(ctx context.Context, next log.Logger) log.Logger {
+	fields, ok := ctx.Value(ctxLogFields).([]interface{})
+	if !ok {
+		return next
+	}
+	return log.With(next, fields...)
+}
+
+// KeyValuesOption configures LoggerWithKeyValues.
+type KeyValuesOption func(*keyvalsLogger)
+
+// KeyValuesWithDuplicateKeys sets how the keys embedded in several layers of an error are handled,
+// DuplicateKeysOuter by default.
+func KeyValuesWithDuplicateKeys(policy DuplicateKeys) KeyValuesOption {
+	return func(l *keyvalsLogger) {
+		l.duplicateKeys = policy
+	}
+}
+
+// LoggerWithKeyValues wraps next and adds the key values embedded in the whole error chain to log entries when available.
+func LoggerWithKeyValues(next log.Logger, opts ...KeyValuesOption) log.Logger {
+	l := &keyvalsLogger{
 		next: next,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 type keyvalsLogger struct {
-	next log.Logger
+	next          log.Logger
+	duplicateKeys DuplicateKeys
 }
 
 func (l *keyvalsLogger) Log(keyvals ...interface{}) error {
-	for i := 0; i < len(keyvals); i += 2 {
-		if keyvals[i] == "err" {
+	keyvals = evenKeyValues(keyvals)
+	n := len(keyvals)
+	for i := 0; i < n; i += 2 {
+		if isErrorKey(keyvals[i]) {
 			if err, ok := keyvals[i+1].(error); ok {
-				if newKeyvals, ok := HasKeyValues(err); ok {
-					keyvals = append(keyvals, newKeyvals...)
+				if newKeyvals, ok := HasKeyValuesWithPolicy(err, l.duplicateKeys); ok {
+					keyvals = append(slices.Clip(keyvals), newKeyvals...)
 				}
 			}
 		}
@@ -76,38 +179,215 @@ func (l *keyvalsLogger) Log(keyvals ...interface{}) error {
 	return l.next.Log(keyvals...)
 }
 
+// SentryOption configures LoggerWithSentry.
+type SentryOption func(*sentryLogger)
+
+// SentryWithRedaction redacts the errors and the key values before exporting them.
+func SentryWithRedaction(redaction *Redaction) SentryOption {
+	return func(l *sentryLogger) {
+		l.redaction = redaction
+	}
+}
+
+// SentryWithFingerprint sets the fingerprint of the exported events, which defines how Sentry groups them.
+// A nil fingerprint keeps the default grouping.
+func SentryWithFingerprint(fingerprint func(err error, keyvals []interface{}) []string) SentryOption {
+	return func(l *sentryLogger) {
+		l.fingerprint = fingerprint
+	}
+}
+
+// FingerprintByKey groups the events by their default grouping and the value of a key,
+// such as "code", so that errors with the same stack but different error codes are told apart.
+func FingerprintByKey(key string) func(err error, keyvals []interface{}) []string {
+	return func(err error, keyvals []interface{}) []string {
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			if keyvals[i] == key {
+				return []string{"{{ default }}", fmt.Sprint(keyvals[i+1])}
+			}
+		}
+		return nil
+	}
+}
+
+// SentryWithBreadcrumbs records the log lines without error as breadcrumbs on the hub from the context,
+// so that captured exceptions show the trail of what the request did. At most limit breadcrumbs are kept.
+// Only the lines at the given levels are recorded, debug and info by default. Lines without level count as info.
+func SentryWithBreadcrumbs(limit int, levels ...level.Value) SentryOption {
+	if len(levels) == 0 {
+		levels = []level.Value{level.DebugValue(), level.InfoValue()}
+	}
+	return func(l *sentryLogger) {
+		l.breadcrumbLimit = limit
+		l.breadcrumbLevels = levels
+	}
+}
+
 // LoggerWithSentry exports errors to sentry.
-func LoggerWithSentry(ctx context.Context, next log.Logger) log.Logger {
-	return &sentryLogger{
+// The other key values of the log line and the ones embedded in the errors are sent as extra data,
+// the request context as the event request and the error behaviors as tags.
+func LoggerWithSentry(ctx context.Context, next log.Logger, opts ...SentryOption) log.Logger {
+	l := &sentryLogger{
 		ctx:  ctx,
 		next: next,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 type sentryLogger struct {
-	ctx  context.Context
-	next log.Logger
+	ctx         context.Context
+	next        log.Logger
+	redaction   *Redaction
+	fingerprint func(err error, keyvals []interface{}) []string
+
+	breadcrumbLimit  int
+	breadcrumbLevels []level.Value
 }
 
 func (l *sentryLogger) Log(keyvals ...interface{}) error {
-	for i := 0; i < len(keyvals); i += 2 {
-		switch keyvals[i] {
-		case "err", "error":
+	captured := false
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if isErrorKey(keyvals[i]) {
 			var e error
 			switch err := keyvals[i+1].(type) {
 			case error:
 				e = err
 			case string:
-				e = errors.New(err)
+				e = messageError(err)
 			}
 			if e != nil {
-				if hub := sentry.GetHubFromContext(l.ctx); hub != nil {
-					hub.CaptureException(e)
-				} else {
-					sentry.CaptureException(e)
+				captured = true
+				if IsErrReported(e) || !markReported(l.ctx, e) {
+					continue
 				}
+				l.capture(e, keyvals)
+				// Stacked Sentry loggers down the chain must not export the error again.
+				keyvals = slices.Clone(keyvals)
+				keyvals[i+1] = WithErrReported(e)
 			}
 		}
 	}
+	if !captured && l.breadcrumbLimit > 0 {
+		l.addBreadcrumb(keyvals)
+	}
 	return l.next.Log(keyvals...)
 }
+
+var sentryLevels = map[string]sentry.Level{
+	level.DebugValue().String(): sentry.LevelDebug,
+	level.InfoValue().String():  sentry.LevelInfo,
+	level.WarnValue().String():  sentry.LevelWarning,
+	level.ErrorValue().String(): sentry.LevelError,
+}
+
+func (l *sentryLogger) addBreadcrumb(keyvals []interface{}) {
+	// Breadcrumbs are scoped to a request, so the global hub is never used.
+	hub := sentry.GetHubFromContext(l.ctx)
+	if hub == nil {
+		return
+	}
+	lvl := level.InfoValue().String()
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == level.Key() {
+			lvl = fmt.Sprint(keyvals[i+1])
+		}
+	}
+	if !slices.ContainsFunc(l.breadcrumbLevels, func(v level.Value) bool { return v.String() == lvl }) {
+		return
+	}
+
+	breadcrumb := &sentry.Breadcrumb{
+		Type:      "default",
+		Category:  "log",
+		Level:     sentryLevels[lvl],
+		Data:      make(map[string]interface{}),
+		Timestamp: time.Now(),
+	}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch keyvals[i] {
+		case level.Key():
+		case "msg":
+			breadcrumb.Message = fmt.Sprint(l.redaction.Value(keyvals[i], keyvals[i+1]))
+		default:
+			breadcrumb.Data[fmt.Sprint(keyvals[i])] = sentryExtra(l.redaction.Value(keyvals[i], keyvals[i+1]))
+		}
+	}
+	hub.Scope().AddBreadcrumb(breadcrumb, l.breadcrumbLimit)
+}
+
+func (l *sentryLogger) capture(e error, keyvals []interface{}) {
+	hub := sentry.GetHubFromContext(l.ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		extras := make(map[string]interface{})
+		addExtras := func(keyvals []interface{}) {
+			for i := 0; i+1 < len(keyvals); i += 2 {
+				if isErrorKey(keyvals[i]) {
+					continue
+				}
+				extras[fmt.Sprint(keyvals[i])] = sentryExtra(l.redaction.Value(keyvals[i], keyvals[i+1]))
+			}
+		}
+		addExtras(keyvals)
+		if errKeyvals, ok := HasKeyValues(e); ok {
+			addExtras(errKeyvals)
+		}
+		scope.SetExtras(extras)
+
+		scope.SetTags(map[string]string{
+			"error.not_found":  strconv.FormatBool(IsErrNotFound(e)),
+			"error.validation": strconv.FormatBool(IsErrValidation(e)),
+			"error.retriable":  strconv.FormatBool(IsErrRetriable(e)),
+		})
+
+		if id, err := GetRequestID(l.ctx); err == nil {
+			scope.SetTag("request_id", id)
+		}
+		if info, err := GetRequestInfo(l.ctx); err == nil {
+			if info.Route != "" {
+				scope.SetTag("route", info.Route)
+			}
+			scope.AddEventProcessor(func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+				if event.Request == nil {
+					event.Request = &sentry.Request{
+						Method:  info.Method,
+						URL:     info.Path,
+						Headers: map[string]string{"User-Agent": info.UserAgent},
+					}
+				}
+				if event.User.ID == "" {
+					event.User.ID = info.Subject
+				}
+				if event.User.IPAddress == "" {
+					event.User.IPAddress = info.RemoteIP
+				}
+				return event
+			})
+		}
+
+		if l.fingerprint != nil {
+			if fingerprint := l.fingerprint(e, keyvals); fingerprint != nil {
+				scope.SetFingerprint(fingerprint)
+			}
+		}
+
+		// The errors redacted by LoggerWithRedaction must not be unwrapped either.
+		hub.CaptureException(l.redaction.Error(e))
+	})
+}
+
+// sentryExtra converts values that do not marshal to JSON meaningfully.
+func sentryExtra(v interface{}) interface{} {
+	switch x := v.(type) {
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
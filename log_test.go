@@ -0,0 +1,128 @@
+package toolbox
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+)
+
+// fakeTransport records the events sent to Sentry.
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *fakeTransport) Configure(sentry.ClientOptions) {}
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+func (t *fakeTransport) Flush(time.Duration) bool              { return true }
+func (t *fakeTransport) FlushWithContext(context.Context) bool { return true }
+func (t *fakeTransport) Close()                                {}
+
+func (t *fakeTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.events)
+}
+
+// newSentryContext returns a request context with a hub sending to a fake transport.
+func newSentryContext(t *testing.T) (context.Context, *fakeTransport) {
+	t.Helper()
+	transport := &fakeTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.invalid/1", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := sentry.SetHubOnContext(context.Background(), sentry.NewHub(client, sentry.NewScope()))
+	return WithErrorReporting(ctx), transport
+}
+
+// uncomparableError cannot be compared with == although its type is comparable.
+type uncomparableError struct {
+	details any
+}
+
+func (e uncomparableError) Error() string { return "uncomparable" }
+
+func TestLoggerWithSentryReportsOnce(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(ctx context.Context, err error)
+	}{
+		{"stacked loggers", func(ctx context.Context, err error) {
+			logger := LoggerWithSentry(ctx, LoggerWithSentry(ctx, log.NewNopLogger()))
+			logger.Log("err", err)
+		}},
+		{"repeated logs", func(ctx context.Context, err error) {
+			LoggerWithSentry(ctx, log.NewNopLogger()).Log("err", err)
+			LoggerWithSentry(ctx, log.NewNopLogger()).Log("error", WithKeyValues(err, "retry", 1))
+		}},
+		{"wrapped with %w", func(ctx context.Context, err error) {
+			LoggerWithSentry(ctx, log.NewNopLogger()).Log("err", err)
+			LoggerWithSentry(ctx, log.NewNopLogger()).Log("err", fmt.Errorf("handling: %w", err))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, transport := newSentryContext(t)
+			tt.log(ctx, errors.New("boom"))
+			if n := transport.count(); n != 1 {
+				t.Errorf("got %d events, want 1", n)
+			}
+		})
+	}
+}
+
+func TestLoggerWithSentryUncomparableErrors(t *testing.T) {
+	ctx, transport := newSentryContext(t)
+	logger := LoggerWithSentry(ctx, log.NewNopLogger())
+	logger.Log("err", uncomparableError{details: []string{"a"}})
+	logger.Log("err", uncomparableError{details: []string{"a"}})
+	if n := transport.count(); n != 2 {
+		t.Errorf("got %d events, want 2", n)
+	}
+}
+
+func TestLoggerWithSentryErrorsWrappingTheSameSentinel(t *testing.T) {
+	ctx, transport := newSentryContext(t)
+	logger := LoggerWithSentry(ctx, log.NewNopLogger())
+	logger.Log("err", errors.Wrap(sql.ErrNoRows, "load user"))
+	logger.Log("err", errors.Wrap(sql.ErrNoRows, "load org"))
+	logger.Log("err", fmt.Errorf("read body: %w", io.EOF))
+	logger.Log("err", fmt.Errorf("read header: %w", io.EOF))
+	if n := transport.count(); n != 4 {
+		t.Errorf("got %d events, want 4", n)
+	}
+}
+
+func TestWithLogFields(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), log.NewLogfmtLogger(&buf))
+	ctx = WithLogFields(ctx, "tenant", "acme")
+
+	keyvals := make([]interface{}, 1, 2)
+	keyvals[0] = "job"
+	jobCtx := WithLogFields(ctx, keyvals...)
+	if extra := keyvals[:2][1]; extra != nil {
+		t.Errorf("the backing array of the caller was written: %v", extra)
+	}
+
+	Logger(jobCtx).Log("msg", "hello")
+	Logger(ctx).Log("msg", "bye")
+	want := "tenant=acme job=(MISSING) msg=hello\ntenant=acme msg=bye\n"
+	if buf.String() != want {
+		t.Errorf("logged %q, want %q", buf.String(), want)
+	}
+}
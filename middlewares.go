@@ -3,41 +3,414 @@ package toolbox
 import (
 	"context"
 	"errors"
+	"math/rand/v2"
 	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
 )
 
 type key string
 
 const (
-	reqContextMethod key = "toolbox_req_context_method"
-	reqContextPath   key = "toolbox_req_context_path"
+	reqContextInfo     key = "toolbox_req_context_info"
+	reqContextReported key = "toolbox_req_context_reported"
+	reqContextID       key = "toolbox_req_context_id"
 )
 
-// GetRequestContext returns a logger from a context.
-func GetRequestContext(ctx context.Context) (method, path string, err error) {
-	method, ok := ctx.Value(reqContextMethod).(string)
+// RequestIDHeader is the header carrying the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestInfo describes the request being served.
+type RequestInfo struct {
+	Method string
+	Path   string
+	// Route is the pattern matched by http.ServeMux, such as "GET /users/{id}".
+	// It is empty when the request was not routed by a ServeMux, or when a middleware other than the toolbox ones
+	// replaced the request between the RequestContext middleware and the ServeMux.
+	Route     string
+	RemoteIP  string
+	UserAgent string
+	// Subject identifies the authenticated caller. It is set by SetRequestSubject.
+	Subject string
+}
+
+type requestInfo struct {
+	mu   sync.Mutex
+	info RequestInfo
+	// request is the request passed to the next handler, which the ServeMux sets the pattern of when routing.
+	// It is kept up to date by trackRequest.
+	request *http.Request
+}
+
+// GetRequestInfo returns the request info from a context.
+func GetRequestInfo(ctx context.Context) (RequestInfo, error) {
+	ri, ok := ctx.Value(reqContextInfo).(*requestInfo)
 	if !ok {
-		return "", "", errors.New("request method not found")
+		return RequestInfo{}, errors.New("request info not found")
+	}
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	info := ri.info
+	if info.Route == "" && ri.request != nil {
+		info.Route = ri.request.Pattern
+	}
+	return info, nil
+}
+
+// trackRequest makes the request info follow r, which the ServeMux sets the pattern of.
+// Middlewares replacing the request with a new context must call it.
+func trackRequest(r *http.Request) {
+	if ri, ok := r.Context().Value(reqContextInfo).(*requestInfo); ok {
+		ri.mu.Lock()
+		defer ri.mu.Unlock()
+		ri.request = r
 	}
-	path, ok = ctx.Value(reqContextPath).(string)
+}
+
+// SetRequestSubject sets the authenticated caller of the request, so that it is logged from then on.
+// It is meant to be called by authentication middlewares and does nothing without the RequestContext middleware.
+func SetRequestSubject(ctx context.Context, subject string) {
+	ri, ok := ctx.Value(reqContextInfo).(*requestInfo)
 	if !ok {
-		return "", "", errors.New("request path not found")
+		return
+	}
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	ri.info.Subject = subject
+}
+
+// GetRequestContext returns the method and path of the request from a context.
+func GetRequestContext(ctx context.Context) (method, path string, err error) {
+	info, err := GetRequestInfo(ctx)
+	if err != nil {
+		return "", "", err
 	}
-	return method, path, nil
+	return info.Method, info.Path, nil
+}
+
+// GetRequestID returns the request ID from a context.
+func GetRequestID(ctx context.Context) (string, error) {
+	id, ok := ctx.Value(reqContextID).(string)
+	if !ok {
+		return "", errors.New("request id not found")
+	}
+	return id, nil
+}
+
+// WithRequestID returns a context carrying a request ID, for requests that do not go through the RequestContext middleware.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, reqContextID, id)
 }
 
-type requestContext struct{}
+// RequestContextOption configures the RequestContext middleware.
+type RequestContextOption func(*requestContext)
+
+// WithTrustedProxies makes the middleware take the client IP from the X-Forwarded-For and X-Real-IP headers
+// when the request comes from one of the given networks. The headers are ignored otherwise, as anyone could set them.
+func WithTrustedProxies(proxies ...netip.Prefix) RequestContextOption {
+	return func(r *requestContext) {
+		r.trustedProxies = append(r.trustedProxies, proxies...)
+	}
+}
+
+type requestContext struct {
+	trustedProxies []netip.Prefix
+}
 
 // NewRequestContext returns a new RequestContext middleware.
-func NewRequestContext() func(next http.Handler) http.Handler {
+// The request ID is taken from the X-Request-ID header, or from the trace ID of the traceparent header,
+// or generated otherwise. It is echoed in the X-Request-ID response header.
+func NewRequestContext(opts ...RequestContextOption) func(next http.Handler) http.Handler {
 	l := &requestContext{}
+	for _, opt := range opts {
+		opt(l)
+	}
 	return l.middleware
 }
 
-func (r *requestContext) middleware(next http.Handler) http.Handler {
+func (l *requestContext) middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.WithValue(r.Context(), reqContextMethod, r.Method)
-		ctx = context.WithValue(ctx, reqContextPath, r.URL.Path)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		ri := &requestInfo{
+			info: RequestInfo{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Route:     r.Pattern,
+				RemoteIP:  l.clientIP(r),
+				UserAgent: r.UserAgent(),
+			},
+		}
+		ctx := context.WithValue(r.Context(), reqContextInfo, ri)
+		ctx = WithErrorReporting(ctx)
+		id := requestID(r)
+		ctx = WithRequestID(ctx, id)
+		w.Header().Set(RequestIDHeader, id)
+		r = r.WithContext(ctx)
+		trackRequest(r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP of the client, walking the X-Forwarded-For header back from the trusted proxies.
+func (l *requestContext) clientIP(r *http.Request) string {
+	addr, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	ip := addr.Addr().Unmap()
+	if !l.trusted(ip) {
+		return ip.String()
+	}
+	var forwarded []string
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		forwarded = strings.Split(strings.Join(values, ","), ",")
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			// The headers cannot be trusted beyond an invalid hop.
+			return ip.String()
+		}
+		ip = hop.Unmap()
+		if !l.trusted(ip) {
+			return ip.String()
+		}
+	}
+	if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return realIP.Unmap().String()
+	}
+	return ip.String()
+}
+
+func (l *requestContext) trusted(ip netip.Addr) bool {
+	for _, proxy := range l.trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	requestIDRegex   = regexp.MustCompile(`^[\w\-.:/+=]{1,128}$`)
+	traceparentRegex = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}`)
+)
+
+// requestID returns the ID of a request, ignoring headers that could be used to inject garbage in the logs.
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); requestIDRegex.MatchString(id) {
+		return id
+	}
+	if matches := traceparentRegex.FindStringSubmatch(r.Header.Get("traceparent")); len(matches) == 2 && matches[1] != strings.Repeat("0", 32) {
+		return matches[1]
+	}
+	return uuid.NewString()
+}
+
+type reportedErrors struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+// WithErrorReporting returns a context tracking the errors exported to Sentry, so that each error is exported
+// only once whatever the number of times it is logged, as is or wrapped. The RequestContext middleware already sets it.
+func WithErrorReporting(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reqContextReported, &reportedErrors{})
+}
+
+// markReported records err as reported and returns false if it, or an error it wraps, already was.
+// Errors are told apart by identity rather than by root cause, so that unrelated errors wrapping the same
+// sentinel, such as sql.ErrNoRows, are all reported.
+func markReported(ctx context.Context, err error) bool {
+	reported, ok := ctx.Value(reqContextReported).(*reportedErrors)
+	if !ok {
+		return true
+	}
+	// LoggerWithRedaction wraps the logged errors anew on every log line, so the original error is recorded instead.
+	for {
+		redacted, ok := err.(*redactedChain)
+		if !ok {
+			break
+		}
+		err = redacted.cause
+	}
+	reported.mu.Lock()
+	defer reported.mu.Unlock()
+	for layer := err; layer != nil; layer = nextCause(layer) {
+		for _, reportedErr := range reported.errors {
+			if sameError(reportedErr, layer) {
+				return false
+			}
+		}
+	}
+	reported.errors = append(reported.errors, err)
+	return true
+}
+
+// sameError reports whether a and b are equal, errors that cannot be compared never being.
+func sameError(a, b error) (same bool) {
+	// Comparing interfaces panics when their dynamic types match but are not comparable,
+	// which is not always known from the type, for example with structs holding interfaces.
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
+
+// AccessLogOption configures the AccessLog middleware.
+type AccessLogOption func(*accessLog)
+
+// AccessLogWithSampling logs only the given ratio of the requests, between 0 and 1.
+// Server errors are always logged.
+func AccessLogWithSampling(ratio float64) AccessLogOption {
+	return func(l *accessLog) {
+		l.sampling = ratio
+	}
+}
+
+// AccessLogWithExclusions does not log the requests to the given paths, such as health checks.
+func AccessLogWithExclusions(paths ...string) AccessLogOption {
+	return func(l *accessLog) {
+		for _, path := range paths {
+			l.exclusions[path] = struct{}{}
+		}
+	}
+}
+
+type accessLog struct {
+	logger     log.Logger
+	sampling   float64
+	exclusions map[string]struct{}
+}
+
+// NewAccessLog returns a new AccessLog middleware, logging one line per request with its status,
+// the number of bytes of its body and its duration.
+// It should be wrapped by the RequestContext middleware so that the lines carry the request context.
+func NewAccessLog(logger log.Logger, opts ...AccessLogOption) func(next http.Handler) http.Handler {
+	l := &accessLog{
+		logger:     logger,
+		sampling:   1,
+		exclusions: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l.middleware
+}
+
+func (l *accessLog) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := l.exclusions[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		rw := WrapResponseWriter(w)
+		// The panics are not recovered, so that recovery middlewares get the stack where they were raised.
+		panicked := true
+		defer func() {
+			status := responseStatus(rw, panicked)
+			if status >= 500 || l.sampling >= 1 || rand.Float64() < l.sampling {
+				LogInfo(LoggerWithRequestContext(r.Context(), l.logger),
+					"status", status,
+					"bytes", rw.BytesWritten(),
+					"duration", time.Since(start),
+				)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+		panicked = false
+	})
+}
+
+// responseStatus returns the status of the response once the next handler returned or panicked.
+func responseStatus(rw ResponseWriter, panicked bool) int {
+	status := rw.Status()
+	switch {
+	case status == 0 && panicked:
+		// The panic is rendered as a 500 by a recovery middleware or net/http aborts the response.
+		return http.StatusInternalServerError
+	case status == 0:
+		// Nothing was written, which net/http answers with a 200.
+		return http.StatusOK
+	}
+	return status
+}
+
+type sentryHub struct{}
+
+// NewSentryHub returns a new SentryHub middleware, which puts a clone of the current Sentry hub in the request context
+// so that the events of concurrent requests do not share their scope. The scope carries the request, the request ID
+// and route tags and the client as user, identified by the subject once known.
+// It also starts a transaction, continuing the trace of the sentry-trace header, which is finished with the response status.
+// It should be wrapped by the RequestContext middleware so that the request context is available.
+func NewSentryHub() func(next http.Handler) http.Handler {
+	l := &sentryHub{}
+	return l.middleware
+}
+
+func (l *sentryHub) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub := sentry.CurrentHub().Clone()
+		ctx := sentry.SetHubOnContext(r.Context(), hub)
+
+		scope := hub.Scope()
+		scope.SetRequest(r)
+		if id, err := GetRequestID(ctx); err == nil {
+			scope.SetTag("request_id", id)
+		}
+		// The route and the subject are only known once the request is routed and authenticated.
+		scope.AddEventProcessor(func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			info, err := GetRequestInfo(ctx)
+			if err != nil {
+				return event
+			}
+			if info.Route != "" {
+				if event.Tags == nil {
+					event.Tags = make(map[string]string)
+				}
+				event.Tags["route"] = info.Route
+			}
+			if event.User.ID == "" {
+				event.User.ID = info.Subject
+			}
+			if event.User.IPAddress == "" {
+				event.User.IPAddress = info.RemoteIP
+			}
+			return event
+		})
+
+		transaction := sentry.StartTransaction(ctx, r.Method+" "+r.URL.Path,
+			sentry.ContinueTrace(hub, r.Header.Get(sentry.SentryTraceHeader), r.Header.Get(sentry.SentryBaggageHeader)),
+			sentry.WithOpName("http.server"),
+			sentry.WithTransactionSource(sentry.SourceURL),
+		)
+		transaction.SetData("http.request.method", r.Method)
+
+		rw := WrapResponseWriter(w)
+		// The panics are not recovered, so that recovery middlewares get the stack where they were raised.
+		panicked := true
+		defer func() {
+			status := responseStatus(rw, panicked)
+			if info, err := GetRequestInfo(ctx); err == nil && info.Route != "" {
+				transaction.Name = info.Route
+				transaction.Source = sentry.SourceRoute
+			}
+			transaction.Status = sentry.HTTPtoSpanStatus(status)
+			transaction.SetData("http.response.status_code", status)
+			transaction.Finish()
+		}()
+		r = r.WithContext(transaction.Context())
+		trackRequest(r)
+		next.ServeHTTP(rw, r)
+		panicked = false
 	})
 }
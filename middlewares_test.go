@@ -0,0 +1,161 @@
+package toolbox
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+)
+
+func TestAccessLogPanic(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewAccessLog(log.NewLogfmtLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("recovered %v, want the panic to be propagated", recovered)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	if !strings.Contains(buf.String(), "status=500") {
+		t.Errorf("access log %q does not contain status=500", buf.String())
+	}
+}
+
+func TestSentryHubPanic(t *testing.T) {
+	transport := &fakeTransport{}
+	if err := sentry.Init(sentry.ClientOptions{Dsn: "https://key@sentry.invalid/1", Transport: transport, EnableTracing: true, TracesSampleRate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	defer sentry.CurrentHub().BindClient(nil)
+
+	handler := NewSentryHub()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("recovered %v, want the panic to be propagated", recovered)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	if len(transport.events) != 1 || transport.events[0].Type != "transaction" {
+		t.Fatalf("got events %v, want one transaction", transport.events)
+	}
+	if trace := transport.events[0].Contexts["trace"]; trace["status"] != sentry.HTTPtoSpanStatus(http.StatusInternalServerError) {
+		t.Errorf("transaction status = %v, want %v", trace["status"], sentry.HTTPtoSpanStatus(http.StatusInternalServerError))
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		name        string
+		requestID   string
+		traceparent string
+		want        string
+	}{
+		{"request ID", "req-42", "", "req-42"},
+		{"request ID over traceparent", "req-42", "00-" + traceID + "-00f067aa0ba902b7-01", "req-42"},
+		{"traceparent", "", "00-" + traceID + "-00f067aa0ba902b7-01", traceID},
+		{"invalid request ID", "req 42\nlevel=error", "00-" + traceID + "-00f067aa0ba902b7-01", traceID},
+		{"too long request ID", strings.Repeat("a", 129), "", ""},
+		{"uppercase traceparent", "", "00-" + strings.ToUpper(traceID) + "-00f067aa0ba902b7-01", ""},
+		{"short traceparent", "", "00-" + traceID[:30] + "-00f067aa0ba902b7-01", ""},
+		{"zero trace ID", "", "00-" + strings.Repeat("0", 32) + "-00f067aa0ba902b7-01", ""},
+		{"garbage traceparent", "", "not a traceparent", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := NewRequestContext()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = GetRequestID(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if tt.want != "" && got != tt.want {
+				t.Errorf("request ID = %q, want %q", got, tt.want)
+			}
+			// Rejected headers give a generated ID.
+			if tt.want == "" {
+				if _, err := uuid.Parse(got); err != nil {
+					t.Errorf("request ID = %q, want a generated UUID", got)
+				}
+			}
+			if header := w.Header().Get(RequestIDHeader); header != got {
+				t.Errorf("response header = %q, want the request ID %q", header, got)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   []string
+		realIP         string
+		trustedProxies []netip.Prefix
+		want           string
+	}{
+		{"no proxy", "203.0.113.7:1234", nil, "", proxies, "203.0.113.7"},
+		{"untrusted remote", "203.0.113.7:1234", []string{"198.51.100.1"}, "198.51.100.2", proxies, "203.0.113.7"},
+		{"no trusted proxies", "10.0.0.1:1234", []string{"198.51.100.1"}, "", nil, "10.0.0.1"},
+		{"trusted proxy", "10.0.0.1:1234", []string{"198.51.100.1"}, "", proxies, "198.51.100.1"},
+		{"proxy chain", "10.0.0.1:1234", []string{"198.51.100.1, 10.0.0.3", "10.0.0.2"}, "", proxies, "198.51.100.1"},
+		{"spoofed forwarded for", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1"}, "", proxies, "198.51.100.1"},
+		{"invalid hop", "10.0.0.1:1234", []string{"198.51.100.1, garbage, 10.0.0.2"}, "", proxies, "10.0.0.2"},
+		{"only trusted hops", "10.0.0.1:1234", []string{"10.0.0.2"}, "", proxies, "10.0.0.2"},
+		{"real IP behind trusted hops", "10.0.0.1:1234", []string{"10.0.0.2"}, "198.51.100.9", proxies, "198.51.100.9"},
+		{"real IP without forwarded for", "10.0.0.1:1234", nil, "198.51.100.9", proxies, "198.51.100.9"},
+		{"invalid real IP", "10.0.0.1:1234", nil, "garbage", proxies, "10.0.0.1"},
+		{"real IP ignored after an untrusted hop", "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.9", proxies, "198.51.100.1"},
+		{"IPv6 proxy", "[2001:db8::1]:1234", []string{"2001:db8:ffff::1", "198.51.100.1"}, "", proxies, "198.51.100.1"},
+		{"IPv4-mapped remote", "[::ffff:10.0.0.1]:1234", []string{"198.51.100.1"}, "", proxies, "198.51.100.1"},
+		{"IPv4-mapped hop", "10.0.0.1:1234", []string{"::ffff:198.51.100.1"}, "", proxies, "198.51.100.1"},
+		{"unparsable remote address", "pipe", []string{"198.51.100.1"}, "", proxies, "pipe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			var got string
+			handler := NewRequestContext(WithTrustedProxies(tt.trustedProxies...))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				info, _ := GetRequestInfo(r.Context())
+				got = info.RemoteIP
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("remote IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package toolbox
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces redacted values.
+const RedactedValue = "[REDACTED]"
+
+// Redactor is implemented by values that must not be logged as is.
+type Redactor interface {
+	// Redacted returns the value to log instead.
+	Redacted() any
+}
+
+// Redaction redacts sensitive values by key and by pattern.
+// A nil Redaction only redacts values implementing Redactor.
+type Redaction struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// NewRedaction returns a Redaction replacing the values of the given keys (case insensitive)
+// and the substrings of string values and error messages matching the patterns.
+func NewRedaction(keys []string, patterns ...*regexp.Regexp) *Redaction {
+	r := &Redaction{
+		keys:     make(map[string]struct{}, len(keys)),
+		patterns: patterns,
+	}
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+	return r
+}
+
+// String redacts the substrings matching the patterns.
+func (r *Redaction) String(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllLiteralString(s, RedactedValue)
+	}
+	return s
+}
+
+// Value redacts the value of a key.
+func (r *Redaction) Value(key, value any) any {
+	if redactor, ok := value.(Redactor); ok {
+		return redactor.Redacted()
+	}
+	if r == nil {
+		return value
+	}
+	if k, ok := key.(string); ok {
+		if _, ok := r.keys[strings.ToLower(k)]; ok {
+			return RedactedValue
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return r.String(v)
+	case error:
+		return r.errorValue(v)
+	case fmt.Stringer:
+		if s := v.String(); r.String(s) != s {
+			return r.String(s)
+		}
+	}
+	return value
+}
+
+// errorValue redacts the message of an error and the key values embedded in its chain, so that loggers
+// such as LoggerWithKeyValues further down the chain only see redacted values.
+// The redacted error wraps err, so that its stack trace and behaviors are still found.
+func (r *Redaction) errorValue(err error) any {
+	layers := keyValueLayers(err)
+	msg := r.String(err.Error())
+	if len(layers) == 0 && msg == err.Error() {
+		return err
+	}
+	for i, layer := range layers {
+		layers[i] = r.KeyValues(layer)
+	}
+	return &redactedChain{
+		causerBehavior: &causerBehavior{cause: err},
+		msg:            msg,
+		layers:         layers,
+	}
+}
+
+// KeyValues returns a redacted copy of keyvals.
+func (r *Redaction) KeyValues(keyvals []any) []any {
+	redacted := make([]any, len(keyvals))
+	for i := 0; i < len(keyvals); i += 2 {
+		redacted[i] = keyvals[i]
+		if i+1 < len(keyvals) {
+			redacted[i+1] = r.Value(keyvals[i], keyvals[i+1])
+		}
+	}
+	return redacted
+}
+
+// Map returns a redacted copy of a map such as HTTPError.Params.
+func (r *Redaction) Map(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(m))
+	for k, v := range m {
+		redacted[k] = r.Value(k, v)
+	}
+	return redacted
+}
+
+// Error returns an error with a redacted message that keeps the stack trace of err.
+// The cause of err is hidden so that error reporters cannot unwrap the original message.
+// A nil Redaction only hides the causes of the errors redacted by LoggerWithRedaction.
+func (r *Redaction) Error(err error) error {
+	msg := r.String(err.Error())
+	if msg == err.Error() && findBehavior(err, func(err error) bool { _, ok := err.(*redactedChain); return ok }) == nil {
+		return err
+	}
+	redacted := &redactedError{msg: msg}
+	if foundErr := findBehavior(err, func(err error) bool { _, ok := err.(stackTracer); return ok }); foundErr != nil {
+		redacted.stack = foundErr.(stackTracer).StackTrace()
+	}
+	return redacted
+}
+
+type redactedError struct {
+	msg   string
+	stack errors.StackTrace
+}
+
+func (e *redactedError) Error() string                 { return e.msg }
+func (e *redactedError) StackTrace() errors.StackTrace { return e.stack }
+
+// redactedChain replaces the message and the key values of the error it wraps,
+// whose other behaviors are still found through its cause.
+type redactedChain struct {
+	*causerBehavior
+	msg    string
+	layers [][]interface{}
+}
+
+func (e *redactedChain) Error() string { return e.msg }
+
+// KeyValues returns the redacted key values of every layer of the wrapped error.
+func (e *redactedChain) KeyValues() []interface{} {
+	return slices.Concat(e.layers...)
+}
+
+// LoggerWithRedaction wraps next and redacts sensitive key values.
+// The key values embedded in logged errors are redacted too, so that the loggers of next adding them,
+// such as LoggerWithKeyValues, do not log them in clear. Other wrappers adding key values should be wrapped by it.
+func LoggerWithRedaction(next log.Logger, redaction *Redaction) log.Logger {
+	return &redactionLogger{
+		next:      next,
+		redaction: redaction,
+	}
+}
+
+type redactionLogger struct {
+	next      log.Logger
+	redaction *Redaction
+}
+
+func (l *redactionLogger) Log(keyvals ...interface{}) error {
+	return l.next.Log(l.redaction.KeyValues(keyvals)...)
+}
@@ -0,0 +1,76 @@
+package toolbox
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+)
+
+func TestLoggerWithRedactionRedactsErrorKeyValues(t *testing.T) {
+	var buf bytes.Buffer
+	redaction := NewRedaction([]string{"token"})
+	logger := LoggerWithRedaction(LoggerWithStack(LoggerWithKeyValues(log.NewLogfmtLogger(&buf))), redaction)
+
+	err := WithKeyValues(errors.New("unauthorized"), "token", "s3cr3t", "user", "alice")
+	logger.Log("err", WithKeyValues(err, "attempt", 2))
+
+	out := buf.String()
+	if strings.Contains(out, "s3cr3t") {
+		t.Errorf("log line %q contains the redacted token", out)
+	}
+	for _, want := range []string{"token=" + RedactedValue, "user=alice", "attempt=2", "location=", "err=unauthorized"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log line %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestLoggerWithRedactionKeepsErrorBehaviors(t *testing.T) {
+	ctx, transport := newSentryContext(t)
+	redaction := NewRedaction([]string{"token"}, regexp.MustCompile(`s3cr3t`))
+	logger := LoggerWithRedaction(LoggerWithSentry(ctx, log.NewNopLogger()), redaction)
+
+	err := WithErrNotFound(WithKeyValues(errors.New("no user for token s3cr3t"), "token", "s3cr3t"))
+	logger.Log("err", err)
+	logger.Log("err", err)
+
+	if n := transport.count(); n != 1 {
+		t.Fatalf("got %d events, want 1", n)
+	}
+	event := transport.events[0]
+	if event.Tags["error.not_found"] != "true" {
+		t.Errorf("error.not_found tag = %q, want true", event.Tags["error.not_found"])
+	}
+	if event.Extra["token"] != RedactedValue {
+		t.Errorf("token extra = %v, want it redacted", event.Extra["token"])
+	}
+	for _, exception := range event.Exception {
+		if strings.Contains(exception.Value, "s3cr3t") {
+			t.Errorf("exception %q contains the redacted token", exception.Value)
+		}
+	}
+	if len(event.Exception) == 0 || event.Exception[len(event.Exception)-1].Stacktrace == nil {
+		t.Error("exception has no stack trace, want the one of the redacted error")
+	}
+}
+
+func TestRedactedErrorKeyValuePolicies(t *testing.T) {
+	redaction := NewRedaction([]string{"token"})
+	err := WithKeyValues(WithKeyValues(errors.New("unauthorized"), "token", "inner", "user", "alice"), "user", "bob")
+	redacted := redaction.Value("err", err).(error)
+
+	if keyvals, _ := HasKeyValuesWithPolicy(redacted, DuplicateKeysInner); !slices.Equal(keyvals, []any{"token", RedactedValue, "user", "alice"}) {
+		t.Errorf("inner key values = %v, want the innermost user", keyvals)
+	}
+	if keyvals, _ := HasKeyValues(redacted); !slices.Equal(keyvals, []any{"user", "bob", "token", RedactedValue}) {
+		t.Errorf("outer key values = %v, want the outermost user", keyvals)
+	}
+	if _, ok := HasStack(redacted); !ok {
+		t.Error("HasStack found no stack on the redacted error")
+	}
+}
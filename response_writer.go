@@ -0,0 +1,133 @@
+package toolbox
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter is an http.ResponseWriter recording the status and the size of the response.
+type ResponseWriter interface {
+	http.ResponseWriter
+	// Status returns the status of the response, or 0 if the header was not written yet.
+	Status() int
+	// BytesWritten returns the number of bytes of the body written so far.
+	BytesWritten() int64
+	// Unwrap returns the underlying writer, for http.ResponseController.
+	Unwrap() http.ResponseWriter
+}
+
+// WrapResponseWriter returns a ResponseWriter wrapping w.
+// It implements http.Flusher, http.Hijacker and io.ReaderFrom only when w does, so that handlers
+// detecting these interfaces behave as with w. Writers that are already wrapped are returned as is.
+func WrapResponseWriter(w http.ResponseWriter) ResponseWriter {
+	if rw, ok := w.(ResponseWriter); ok {
+		return rw
+	}
+	rw := &responseWriter{ResponseWriter: w}
+	_, flusher := w.(http.Flusher)
+	_, hijacker := w.(http.Hijacker)
+	_, readerFrom := w.(io.ReaderFrom)
+	switch {
+	case flusher && hijacker && readerFrom:
+		return &struct {
+			*responseWriter
+			flushWriter
+			hijackWriter
+			readerFromWriter
+		}{rw, flushWriter{rw}, hijackWriter{rw}, readerFromWriter{rw}}
+	case flusher && hijacker:
+		return &struct {
+			*responseWriter
+			flushWriter
+			hijackWriter
+		}{rw, flushWriter{rw}, hijackWriter{rw}}
+	case flusher && readerFrom:
+		return &struct {
+			*responseWriter
+			flushWriter
+			readerFromWriter
+		}{rw, flushWriter{rw}, readerFromWriter{rw}}
+	case hijacker && readerFrom:
+		return &struct {
+			*responseWriter
+			hijackWriter
+			readerFromWriter
+		}{rw, hijackWriter{rw}, readerFromWriter{rw}}
+	case flusher:
+		return &struct {
+			*responseWriter
+			flushWriter
+		}{rw, flushWriter{rw}}
+	case hijacker:
+		return &struct {
+			*responseWriter
+			hijackWriter
+		}{rw, hijackWriter{rw}}
+	case readerFrom:
+		return &struct {
+			*responseWriter
+			readerFromWriter
+		}{rw, readerFromWriter{rw}}
+	default:
+		return rw
+	}
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseWriter) Status() int                 { return w.status }
+func (w *responseWriter) BytesWritten() int64         { return w.bytes }
+func (w *responseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func (w *responseWriter) WriteHeader(status int) {
+	// Informational responses are followed by the actual one.
+	if w.status == 0 && (status >= 200 || status == http.StatusSwitchingProtocols) {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+type flushWriter struct{ w *responseWriter }
+
+func (f flushWriter) Flush() {
+	if f.w.status == 0 {
+		f.w.status = http.StatusOK
+	}
+	f.w.ResponseWriter.(http.Flusher).Flush()
+}
+
+type hijackWriter struct{ w *responseWriter }
+
+func (h hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := h.w.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil && h.w.status == 0 {
+		h.w.status = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
+type readerFromWriter struct{ w *responseWriter }
+
+func (r readerFromWriter) ReadFrom(src io.Reader) (int64, error) {
+	if r.w.status == 0 {
+		r.w.status = http.StatusOK
+	}
+	n, err := r.w.ResponseWriter.(io.ReaderFrom).ReadFrom(src)
+	r.w.bytes += n
+	return n, err
+}
@@ -0,0 +1,197 @@
+package toolbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// slogLevels maps the go-kit levels to the slog ones.
+var slogLevels = map[string]slog.Level{
+	level.DebugValue().String(): slog.LevelDebug,
+	level.InfoValue().String():  slog.LevelInfo,
+	level.WarnValue().String():  slog.LevelWarn,
+	level.ErrorValue().String(): slog.LevelError,
+}
+
+func slogToLevel(l slog.Level) level.Value {
+	switch {
+	case l < slog.LevelInfo:
+		return level.DebugValue()
+	case l < slog.LevelWarn:
+		return level.InfoValue()
+	case l < slog.LevelError:
+		return level.WarnValue()
+	default:
+		return level.ErrorValue()
+	}
+}
+
+// Level implements the slog.Leveler interface, so that a DynamicLevel can also filter slog handlers.
+func (l *DynamicLevel) Level() slog.Level {
+	return slogLevels[l.Get().String()]
+}
+
+// NewSlogHandler returns a slog.Handler feeding the records into logger, with the level as a go-kit level
+// and the message under the "msg" key. Groups are flattened into dotted keys.
+// Records below leveler are dropped, none if it is nil.
+func NewSlogHandler(logger log.Logger, leveler slog.Leveler) slog.Handler {
+	return &slogHandler{
+		leveler: leveler,
+		wrap:    func(context.Context, log.Logger) log.Logger { return logger },
+	}
+}
+
+// SlogWithStack wraps next and adds stacktrace to records when available, as LoggerWithStack.
+// As the other slog middlewares, it passes the attributes of groups to next with dotted keys.
+func SlogWithStack(next slog.Handler) slog.Handler {
+	return newSlogMiddleware(next, func(_ context.Context, logger log.Logger) log.Logger {
+		return LoggerWithStack(logger)
+	})
+}
+
+// SlogWithKeyValues wraps next and adds the key values of errors to records when available, as LoggerWithKeyValues.
+func SlogWithKeyValues(next slog.Handler) slog.Handler {
+	return newSlogMiddleware(next, func(_ context.Context, logger log.Logger) log.Logger {
+		return LoggerWithKeyValues(logger)
+	})
+}
+
+// SlogWithRequestContext wraps next and adds the request context of the context passed to the handler,
+// as LoggerWithRequestContext. Loggers must therefore use the context variants of the slog methods.
+func SlogWithRequestContext(next slog.Handler) slog.Handler {
+	return newSlogMiddleware(next, LoggerWithRequestContext)
+}
+
+// SlogWithSentry wraps next and exports errors to Sentry with the hub of the context passed to the handler,
+// as LoggerWithSentry.
+func SlogWithSentry(next slog.Handler, opts ...SentryOption) slog.Handler {
+	return newSlogMiddleware(next, func(ctx context.Context, logger log.Logger) log.Logger {
+		return LoggerWithSentry(ctx, logger, opts...)
+	})
+}
+
+// newSlogMiddleware returns a handler running the records through the go-kit logger returned by wrap,
+// which logs into next. Groups are flattened into dotted keys as next only receives the resulting key values.
+func newSlogMiddleware(next slog.Handler, wrap func(ctx context.Context, logger log.Logger) log.Logger) slog.Handler {
+	return &slogHandler{
+		next: next,
+		wrap: wrap,
+	}
+}
+
+type slogHandler struct {
+	// next is the handler the records go to after wrap, nil if wrap logs them elsewhere.
+	next    slog.Handler
+	leveler slog.Leveler
+	wrap    func(ctx context.Context, logger log.Logger) log.Logger
+	keyvals []interface{}
+	group   string
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if h.next != nil {
+		return h.next.Enabled(ctx, l)
+	}
+	return h.leveler == nil || l >= h.leveler.Level()
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	keyvals := make([]interface{}, 0, 4+len(h.keyvals)+2*r.NumAttrs())
+	keyvals = append(keyvals, level.Key(), slogToLevel(r.Level), "msg", r.Message)
+	keyvals = append(keyvals, h.keyvals...)
+	r.Attrs(func(attr slog.Attr) bool {
+		keyvals = appendAttr(keyvals, h.group, attr)
+		return true
+	})
+	var logger log.Logger = log.NewNopLogger()
+	if h.next != nil {
+		logger = &slogLogger{ctx: ctx, handler: h.next, time: r.Time, pc: r.PC}
+	}
+	return h.wrap(ctx, logger).Log(keyvals...)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.keyvals = append([]interface{}{}, h.keyvals...)
+	for _, attr := range attrs {
+		clone.keyvals = appendAttr(clone.keyvals, h.group, attr)
+	}
+	return &clone
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.group + name + "."
+	return &clone
+}
+
+// appendAttr appends an attribute to keyvals, flattening groups into dotted keys.
+func appendAttr(keyvals []interface{}, group string, attr slog.Attr) []interface{} {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return keyvals
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			group += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			keyvals = appendAttr(keyvals, group, a)
+		}
+		return keyvals
+	}
+	return append(keyvals, group+attr.Key, attr.Value.Any())
+}
+
+// NewSlogLogger returns a go-kit logger feeding the log lines into handler.
+// The go-kit level and the "msg" key become the level and the message of the records, info and empty by default.
+func NewSlogLogger(handler slog.Handler) log.Logger {
+	return &slogLogger{ctx: context.Background(), handler: handler}
+}
+
+type slogLogger struct {
+	ctx     context.Context
+	handler slog.Handler
+	// time and pc are those of the original record when the line comes from a slogHandler.
+	time time.Time
+	pc   uintptr
+}
+
+func (l *slogLogger) Log(keyvals ...interface{}) error {
+	keyvals = evenKeyValues(keyvals)
+	lvl, msg := slog.LevelInfo, ""
+	attrs := make([]slog.Attr, 0, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		switch key := keyvals[i]; {
+		case key == level.Key():
+			if v, ok := slogLevels[fmt.Sprint(keyvals[i+1])]; ok {
+				lvl = v
+				continue
+			}
+		case key == "msg":
+			if s, ok := keyvals[i+1].(string); ok && msg == "" {
+				msg = s
+				continue
+			}
+		}
+		attrs = append(attrs, slog.Any(fmt.Sprint(keyvals[i]), keyvals[i+1]))
+	}
+	if !l.handler.Enabled(l.ctx, lvl) {
+		return nil
+	}
+	t := l.time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r := slog.NewRecord(t, lvl, msg, l.pc)
+	r.AddAttrs(attrs...)
+	return l.handler.Handle(l.ctx, r)
+}
@@ -0,0 +1,152 @@
+package toolbox
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+)
+
+func TestSlogHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+		want string
+	}{
+		{"debug", func(l *slog.Logger) { l.Debug("hello") }, `level=debug msg=hello`},
+		{"info", func(l *slog.Logger) { l.Info("hello", "a", 1) }, `level=info msg=hello a=1`},
+		{"warn", func(l *slog.Logger) { l.Warn("hello") }, `level=warn msg=hello`},
+		{"error", func(l *slog.Logger) { l.Error("hello") }, `level=error msg=hello`},
+		{"custom level", func(l *slog.Logger) { l.Log(context.Background(), slog.LevelWarn+2, "hello") }, `level=warn msg=hello`},
+		{"group", func(l *slog.Logger) { l.WithGroup("g").Info("hello", "a", 1) }, `level=info msg=hello g.a=1`},
+		{"inline group", func(l *slog.Logger) { l.Info("hello", slog.Group("req", "id", 42, slog.Group("user", "id", 7))) }, `level=info msg=hello req.id=42 req.user.id=7`},
+		{"empty group name", func(l *slog.Logger) { l.WithGroup("").Info("hello", "a", 1) }, `level=info msg=hello a=1`},
+		{"attrs", func(l *slog.Logger) { l.With("a", 1).WithGroup("g").With("b", 2).Info("hello", "c", 3) }, `level=info msg=hello a=1 g.b=2 g.c=3`},
+		{"empty attr", func(l *slog.Logger) { l.Info("hello", slog.Attr{}, "a", 1) }, `level=info msg=hello a=1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(slog.New(NewSlogHandler(log.NewLogfmtLogger(&buf), slog.LevelDebug)))
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	lvl := NewDynamicLevel(level.InfoValue())
+	logger := slog.New(NewSlogHandler(log.NewLogfmtLogger(&buf), lvl))
+	logger.Debug("dropped")
+	logger.Info("kept")
+	if got := strings.TrimSpace(buf.String()); got != "level=info msg=kept" {
+		t.Errorf("logged %q, want only the info record", got)
+	}
+}
+
+// recordingHandler records the slog records it handles.
+type recordingHandler struct {
+	level   slog.Level
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(_ context.Context, l slog.Level) bool { return l >= h.level }
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler           { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler                { return h }
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func recordAttrs(r slog.Record) map[string]any {
+	attrs := make(map[string]any)
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value.Any()
+		return true
+	})
+	return attrs
+}
+
+func TestSlogLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyvals   []any
+		wantLevel slog.Level
+		wantMsg   string
+		wantAttrs map[string]any
+	}{
+		{"defaults", []any{"a", 1}, slog.LevelInfo, "", map[string]any{"a": int64(1)}},
+		{"debug", []any{level.Key(), level.DebugValue(), "msg", "hello"}, slog.LevelDebug, "hello", map[string]any{}},
+		{"warn", []any{level.Key(), level.WarnValue(), "msg", "hello"}, slog.LevelWarn, "hello", map[string]any{}},
+		{"error", []any{"msg", "hello", level.Key(), level.ErrorValue(), "a", 1}, slog.LevelError, "hello", map[string]any{"a": int64(1)}},
+		{"unknown level", []any{level.Key(), "fatal"}, slog.LevelInfo, "", map[string]any{"level": "fatal"}},
+		{"non-string message", []any{"msg", 42}, slog.LevelInfo, "", map[string]any{"msg": int64(42)}},
+		{"second message", []any{"msg", "hello", "msg", "again"}, slog.LevelInfo, "hello", map[string]any{"msg": "again"}},
+		{"odd key values", []any{"a"}, slog.LevelInfo, "", map[string]any{"a": log.ErrMissingValue}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &recordingHandler{level: slog.LevelDebug}
+			if err := NewSlogLogger(handler).Log(tt.keyvals...); err != nil {
+				t.Fatal(err)
+			}
+			if len(handler.records) != 1 {
+				t.Fatalf("got %d records, want 1", len(handler.records))
+			}
+			r := handler.records[0]
+			if r.Level != tt.wantLevel || r.Message != tt.wantMsg {
+				t.Errorf("record = %v %q, want %v %q", r.Level, r.Message, tt.wantLevel, tt.wantMsg)
+			}
+			attrs := recordAttrs(r)
+			if len(attrs) != len(tt.wantAttrs) {
+				t.Errorf("attributes = %v, want %v", attrs, tt.wantAttrs)
+			}
+			for k, v := range tt.wantAttrs {
+				if attrs[k] != v {
+					t.Errorf("attribute %s = %v, want %v", k, attrs[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestSlogLoggerDisabledLevel(t *testing.T) {
+	handler := &recordingHandler{level: slog.LevelWarn}
+	NewSlogLogger(handler).Log(level.Key(), level.InfoValue(), "msg", "dropped")
+	if len(handler.records) != 0 {
+		t.Errorf("got %d records, want the info line dropped", len(handler.records))
+	}
+}
+
+func TestSlogLoggerDoesNotWriteIntoTheCallerKeyValues(t *testing.T) {
+	keyvals := make([]any, 1, 2)
+	keyvals[0] = "a"
+	NewSlogLogger(&recordingHandler{}).Log(keyvals...)
+	if extra := keyvals[:2][1]; extra != nil {
+		t.Errorf("the backing array of the caller was written: %v", extra)
+	}
+}
+
+func TestSlogMiddlewaresWithGroupedErrors(t *testing.T) {
+	ctx, transport := newSentryContext(t)
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, nil)
+	logger := slog.New(SlogWithSentry(SlogWithKeyValues(handler)))
+
+	err := WithKeyValues(errors.New("boom"), "user", "alice")
+	logger.WithGroup("job").ErrorContext(ctx, "failed", "err", err)
+
+	if !strings.Contains(buf.String(), "user=alice") {
+		t.Errorf("logged %q, want the key values of the grouped error", buf.String())
+	}
+	if n := transport.count(); n != 1 {
+		t.Errorf("got %d events, want the grouped error exported", n)
+	}
+}
@@ -1,6 +1,17 @@
 package sql
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/solher/toolbox"
+)
 
 // Transaction provides a simple API handling commits and rollbacks.
 func Transaction(db *sqlx.DB, transaction func(tx *sqlx.Tx) error) error {
@@ -16,3 +27,110 @@ func Transaction(db *sqlx.DB, transaction func(tx *sqlx.Tx) error) error {
 	}
 	return tx.Commit()
 }
+
+// tracerName is the name of the OpenTelemetry tracer of the package.
+const tracerName = toolbox.TracerName + "/sql"
+
+// SpanRedaction redacts the queries and the errors recorded on the spans of TransactionContext.
+var SpanRedaction *toolbox.Redaction
+
+// TransactionContext is Transaction with an OpenTelemetry span around the transaction, created with the global
+// tracer provider. The queries made with the context methods of tx create child spans.
+func TransactionContext(ctx context.Context, db *sqlx.DB, transaction func(ctx context.Context, tx *Tx) error) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "sql.transaction",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameKey.String(db.DriverName())),
+	)
+	defer func() {
+		toolbox.RecordSpanError(span, err, SpanRedaction)
+		span.End()
+	}()
+
+	sqlxTx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	tx := &Tx{Tx: sqlxTx, driverName: db.DriverName()}
+	if err := transaction(ctx, tx); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Tx is a transaction tracing the queries made with its context methods.
+type Tx struct {
+	*sqlx.Tx
+	driverName string
+}
+
+func (tx *Tx) startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := "sql.query"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		name = strings.ToUpper(fields[0])
+	}
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNameKey.String(tx.driverName),
+			semconv.DBQueryText(SpanRedaction.String(query)),
+		),
+	)
+}
+
+func endQuery(span trace.Span, err error) {
+	if err != sql.ErrNoRows {
+		toolbox.RecordSpanError(span, err, SpanRedaction)
+	}
+	span.End()
+}
+
+// ExecContext executes a query without returning any rows.
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := tx.startQuery(ctx, query)
+	res, err := tx.Tx.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	return res, err
+}
+
+// NamedExecContext executes a query using named parameters without returning any rows.
+func (tx *Tx) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) {
+	ctx, span := tx.startQuery(ctx, query)
+	res, err := tx.Tx.NamedExecContext(ctx, query, arg)
+	endQuery(span, err)
+	return res, err
+}
+
+// QueryxContext executes a query returning rows. The span covers the query but not the reading of the rows.
+func (tx *Tx) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	ctx, span := tx.startQuery(ctx, query)
+	rows, err := tx.Tx.QueryxContext(ctx, query, args...)
+	endQuery(span, err)
+	return rows, err
+}
+
+// QueryRowxContext executes a query returning at most one row.
+func (tx *Tx) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	ctx, span := tx.startQuery(ctx, query)
+	row := tx.Tx.QueryRowxContext(ctx, query, args...)
+	endQuery(span, row.Err())
+	return row
+}
+
+// GetContext executes a query and scans the single resulting row into dest.
+func (tx *Tx) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	ctx, span := tx.startQuery(ctx, query)
+	err := tx.Tx.GetContext(ctx, dest, query, args...)
+	endQuery(span, err)
+	return err
+}
+
+// SelectContext executes a query and scans the resulting rows into dest.
+func (tx *Tx) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	ctx, span := tx.startQuery(ctx, query)
+	err := tx.Tx.SelectContext(ctx, dest, query, args...)
+	endQuery(span, err)
+	return err
+}
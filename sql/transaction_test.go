@@ -0,0 +1,132 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeDriver answers every query with a single row holding 1, and fails the queries containing "fail".
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if strings.Contains(s.query, "fail") {
+		return nil, errors.New("query failed")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "fail") {
+		return nil, errors.New("query failed")
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"n"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+func newSpanExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func TestTransactionContext(t *testing.T) {
+	exporter := newSpanExporter(t)
+	db := sqlx.MustOpen("fake", "")
+	defer db.Close()
+
+	err := TransactionContext(context.Background(), db, func(ctx context.Context, tx *Tx) error {
+		var n int
+		if err := tx.GetContext(ctx, &n, "SELECT 1"); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE users SET fail = true")
+		return err
+	})
+	if err == nil {
+		t.Fatal("TransactionContext returned no error, want the failed query error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want the transaction and 2 queries", len(spans))
+	}
+	// Spans are exported when they end, so the transaction is last.
+	transaction, selectQuery, updateQuery := spans[2], spans[0], spans[1]
+	if transaction.Name != "sql.transaction" || transaction.Status.Code != codes.Error {
+		t.Errorf("transaction span = %q with status %v, want sql.transaction with an error", transaction.Name, transaction.Status.Code)
+	}
+	for _, query := range []tracetest.SpanStub{selectQuery, updateQuery} {
+		if query.Parent.SpanID() != transaction.SpanContext.SpanID() {
+			t.Errorf("query span %q is not a child of the transaction span", query.Name)
+		}
+	}
+	if selectQuery.Name != "SELECT" || selectQuery.Status.Code != codes.Unset {
+		t.Errorf("select span = %q with status %v, want SELECT without error", selectQuery.Name, selectQuery.Status.Code)
+	}
+	if updateQuery.Name != "UPDATE" || updateQuery.Status.Code != codes.Error {
+		t.Errorf("update span = %q with status %v, want UPDATE with an error", updateQuery.Name, updateQuery.Status.Code)
+	}
+	var text string
+	for _, attr := range updateQuery.Attributes {
+		if attr.Key == attribute.Key("db.query.text") {
+			text = attr.Value.AsString()
+		}
+	}
+	if text != "UPDATE users SET fail = true" {
+		t.Errorf("db.query.text = %q, want the query", text)
+	}
+}
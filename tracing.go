@@ -0,0 +1,94 @@
+package toolbox
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the OpenTelemetry tracers of the toolbox.
+const TracerName = "github.com/solher/toolbox"
+
+// RecordSpanError records err on span and sets its status to error.
+// The error behaviors, the location where it was thrown and its key values are set as span attributes.
+// The message and the key values are redacted with redaction, which may be nil.
+func RecordSpanError(span trace.Span, err error, redaction *Redaction) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		semconv.ErrorTypeKey.String(fmt.Sprintf("%T", rootCause(err))),
+		attribute.Bool("error.not_found", IsErrNotFound(err)),
+		attribute.Bool("error.validation", IsErrValidation(err)),
+		attribute.Bool("error.retriable", IsErrRetriable(err)),
+	}
+	if location, ok := HasStack(err); ok {
+		attrs = append(attrs, attribute.String("error.location", location))
+	}
+	if keyvals, ok := HasKeyValues(err); ok {
+		keyvals = redaction.KeyValues(keyvals)
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			attrs = append(attrs, attribute.String("error."+fmt.Sprint(keyvals[i]), fmt.Sprint(keyvals[i+1])))
+		}
+	}
+	if redaction != nil {
+		err = redaction.Error(err)
+	}
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+type tracing struct{}
+
+// NewTracing returns a new Tracing middleware, which starts an OpenTelemetry server span per request with the
+// global tracer provider, continuing the trace propagated by the headers. The span is named after the route
+// and finished with the response status, server errors setting its status to error.
+// It should be wrapped by the RequestContext middleware so that the route is known, and wrap the other middlewares
+// so that their log lines carry the trace and span IDs.
+func NewTracing() func(next http.Handler) http.Handler {
+	l := &tracing{}
+	return l.middleware
+}
+
+func (l *tracing) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(TracerName).Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rw := WrapResponseWriter(w)
+		// The panics are not recovered, so that recovery middlewares get the stack where they were raised.
+		panicked := true
+		defer func() {
+			status := responseStatus(rw, panicked)
+			route := r.Pattern
+			if info, err := GetRequestInfo(ctx); err == nil {
+				route = info.Route
+			}
+			if route != "" {
+				span.SetName(route)
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		}()
+		r = r.WithContext(ctx)
+		trackRequest(r)
+		next.ServeHTTP(rw, r)
+		panicked = false
+	})
+}
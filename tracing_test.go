@@ -0,0 +1,139 @@
+package toolbox
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newSpanExporter sets the global tracer provider to one exporting to memory for the duration of the test.
+func newSpanExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus codes.Code
+	}{
+		{"ok", http.StatusOK, codes.Unset},
+		{"client error", http.StatusNotFound, codes.Unset},
+		{"server error", http.StatusServiceUnavailable, codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := newSpanExporter(t)
+			var buf bytes.Buffer
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				LoggerWithRequestContext(r.Context(), log.NewLogfmtLogger(&buf)).Log("msg", "hello")
+				w.WriteHeader(tt.status)
+			})
+			handler := NewRequestContext()(NewTracing()(mux))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "GET /users/{id}" {
+				t.Errorf("span name = %q, want the route", span.Name)
+			}
+			if route, _ := spanAttribute(span, "http.route"); route.AsString() != "GET /users/{id}" {
+				t.Errorf("http.route = %q, want the route", route.AsString())
+			}
+			if status, _ := spanAttribute(span, "http.response.status_code"); status.AsInt64() != int64(tt.status) {
+				t.Errorf("http.response.status_code = %d, want %d", status.AsInt64(), tt.status)
+			}
+			if span.Status.Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", span.Status.Code, tt.wantStatus)
+			}
+
+			for _, want := range []string{
+				"trace_id=" + span.SpanContext.TraceID().String(),
+				"span_id=" + span.SpanContext.SpanID().String(),
+			} {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("log line %q does not contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestTracingPanic(t *testing.T) {
+	exporter := newSpanExporter(t)
+	handler := NewTracing()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	func() {
+		defer func() { recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if status, _ := spanAttribute(spans[0], "http.response.status_code"); status.AsInt64() != http.StatusInternalServerError {
+		t.Errorf("http.response.status_code = %d, want 500", status.AsInt64())
+	}
+}
+
+func TestRecordSpanError(t *testing.T) {
+	exporter := newSpanExporter(t)
+	_, span := otel.Tracer(TracerName).Start(context.Background(), "operation")
+	err := WithErrNotFound(WithKeyValues(errors.New("no user for token s3cr3t"), "token", "s3cr3t", "user", "alice"))
+	RecordSpanError(span, err, NewRedaction([]string{"token"}, regexp.MustCompile(`s3cr3t`)))
+	span.End()
+
+	stub := exporter.GetSpans()[0]
+	if stub.Status.Code != codes.Error || strings.Contains(stub.Status.Description, "s3cr3t") {
+		t.Errorf("span status = %+v, want a redacted error", stub.Status)
+	}
+	if len(stub.Events) != 1 {
+		t.Fatalf("got %d events, want the recorded error", len(stub.Events))
+	}
+	attrs := map[attribute.Key]string{}
+	for _, attr := range stub.Events[0].Attributes {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	for key, want := range map[attribute.Key]string{
+		"error.not_found":   "true",
+		"error.token":       RedactedValue,
+		"error.user":        "alice",
+		"exception.message": "no user for token " + RedactedValue,
+	} {
+		if attrs[key] != want {
+			t.Errorf("attribute %s = %q, want %q", key, attrs[key], want)
+		}
+	}
+}
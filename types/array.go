@@ -0,0 +1,225 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Array is a one-dimensional Postgres array that is NULL when nil. It marshals to JSON as a list.
+// Elements are scanned with the sql.Scanner implementation of *T and written with the driver.Valuer
+// implementation of T, so that arrays of types such as Date work. Strings, integers, floats and
+// booleans are supported as well.
+//
+// NULL elements are scanned to the zero value of T. The zero Date is written back as NULL, but other types
+// write their zero value, such as "00:00:00" for Time, so NULL elements of those types do not round-trip.
+type Array[T any] []T
+
+// Value implements the driver.Valuer interface.
+func (n Array[T]) Value() (driver.Value, error) {
+	if n == nil {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, elem := range n {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value, err := arrayElementValue(elem)
+		if err != nil {
+			return nil, fmt.Errorf("array element %d: %w", i, err)
+		}
+		if value == nil {
+			b.WriteString("NULL")
+			continue
+		}
+		b.WriteByte('"')
+		for _, r := range *value {
+			if r == '"' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+func arrayElementValue(elem any) (*string, error) {
+	// Date scans NULL to its zero value, which its Value writes as "0001-01-01".
+	if date, ok := elem.(Date); ok && date.IsZero() {
+		return nil, nil
+	}
+	if valuer, ok := elem.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		elem = v
+	}
+	var s string
+	switch v := elem.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int:
+		s = strconv.Itoa(v)
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		s = strconv.FormatBool(v)
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", elem)
+	}
+	return &s, nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Array[T]) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = nil
+		return nil
+	case string, []byte:
+		elems, err := parseArray(asString(t))
+		if err != nil {
+			return err
+		}
+		array := make(Array[T], len(elems))
+		for i, elem := range elems {
+			if err := scanArrayElement(&array[i], elem); err != nil {
+				return fmt.Errorf("array element %d: %w", i, err)
+			}
+		}
+		*n = array
+		return nil
+	default:
+		return fmt.Errorf("incompatible type %T for Array", value)
+	}
+}
+
+func scanArrayElement(dest any, elem *string) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		if elem == nil {
+			return scanner.Scan(nil)
+		}
+		return scanner.Scan(*elem)
+	}
+	if elem == nil {
+		return errors.New("NULL element requires a type implementing sql.Scanner")
+	}
+	var err error
+	switch d := dest.(type) {
+	case *string:
+		*d = *elem
+	case *int:
+		*d, err = strconv.Atoi(*elem)
+	case *int32:
+		var v int64
+		v, err = strconv.ParseInt(*elem, 10, 32)
+		*d = int32(v)
+	case *int64:
+		*d, err = strconv.ParseInt(*elem, 10, 64)
+	case *float64:
+		*d, err = strconv.ParseFloat(*elem, 64)
+	case *bool:
+		// Postgres writes booleans as t and f.
+		*d, err = strconv.ParseBool(*elem)
+	default:
+		return fmt.Errorf("unsupported type %T", dest)
+	}
+	return err
+}
+
+// parseArray parses a one-dimensional Postgres array literal, NULL elements being nil.
+func parseArray(s string) ([]*string, error) {
+	invalid := func(reason string) error {
+		return &ParseError{Type: "array", Value: s, Reason: reason}
+	}
+	if strings.HasPrefix(s, "[") {
+		return nil, invalid("arrays with explicit bounds are not supported")
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, invalid("expected '{...}'")
+	}
+	inner := s[1 : len(s)-1]
+	elems := []*string{}
+	if strings.TrimSpace(inner) == "" {
+		return elems, nil
+	}
+	for i := 0; ; {
+		for i < len(inner) && inner[i] == ' ' {
+			i++
+		}
+		var b strings.Builder
+		quoted := i < len(inner) && inner[i] == '"'
+		switch {
+		case i < len(inner) && inner[i] == '{':
+			return nil, invalid("multi-dimensional arrays are not supported")
+		case quoted:
+			for i++; ; i++ {
+				if i >= len(inner) {
+					return nil, invalid("unterminated quoted element")
+				}
+				if inner[i] == '\\' && i+1 < len(inner) {
+					i++
+				} else if inner[i] == '"' {
+					i++
+					break
+				}
+				b.WriteByte(inner[i])
+			}
+			for i < len(inner) && inner[i] == ' ' {
+				i++
+			}
+		default:
+			for ; i < len(inner) && inner[i] != ','; i++ {
+				switch inner[i] {
+				case '\\':
+					if i++; i >= len(inner) {
+						return nil, invalid("unterminated escape")
+					}
+				case '"', '{', '}':
+					return nil, invalid(fmt.Sprintf("unexpected %q in unquoted element", inner[i]))
+				}
+				b.WriteByte(inner[i])
+			}
+		}
+
+		elem := b.String()
+		if !quoted {
+			elem = strings.TrimRight(elem, " ")
+			if elem == "" {
+				return nil, invalid("empty unquoted element")
+			}
+		}
+		if !quoted && strings.EqualFold(elem, "NULL") {
+			elems = append(elems, nil)
+		} else {
+			elems = append(elems, &elem)
+		}
+
+		if i >= len(inner) {
+			return elems, nil
+		}
+		if inner[i] != ',' {
+			return nil, invalid(fmt.Sprintf("expected ',' after element %d", len(elems)))
+		}
+		i++
+	}
+}
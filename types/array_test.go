@@ -0,0 +1,105 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseArray(t *testing.T) {
+	null := "<NULL>"
+	tests := []struct {
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{`{}`, []string{}, false},
+		{`{ }`, []string{}, false},
+		{`{a,b,c}`, []string{"a", "b", "c"}, false},
+		{`{ a , b }`, []string{"a", "b"}, false},
+		{`{"a b","c,d","{e}"}`, []string{"a b", "c,d", "{e}"}, false},
+		{`{"a\"b","c\\d"}`, []string{`a"b`, `c\d`}, false},
+		{`{a\,b,c\"d}`, []string{"a,b", `c"d`}, false},
+		{`{"",x}`, []string{"", "x"}, false},
+		{`{NULL,"NULL",null}`, []string{null, "NULL", null}, false},
+		{`{{1,2},{3,4}}`, nil, true},
+		{`[1:2]={a,b}`, nil, true},
+		{`{a,b`, nil, true},
+		{`a,b`, nil, true},
+		{`{"a}`, nil, true},
+		{`{a\}`, nil, true},
+		{`{a,,b}`, nil, true},
+		{`{a,}`, nil, true},
+		{`{a"b}`, nil, true},
+		{`{"a"b}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			elems, err := parseArray(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := []string{}
+			for _, elem := range elems {
+				if elem == nil {
+					got = append(got, null)
+				} else {
+					got = append(got, *elem)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseArray(%s) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrayRoundTrip(t *testing.T) {
+	strs := Array[string]{"a", `b"c`, `d\e`, "f,g", "", "NULL"}
+	value, err := strs.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scannedStrs Array[string]
+	if err := scannedStrs.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(scannedStrs, strs) {
+		t.Errorf("scanned %q from %s, want %q", scannedStrs, value, strs)
+	}
+
+	var ints Array[int64]
+	if err := ints.Scan("{1,-2,3}"); err != nil || !slices.Equal(ints, Array[int64]{1, -2, 3}) {
+		t.Errorf("scanned %v, %v, want [1 -2 3]", ints, err)
+	}
+	if err := ints.Scan("{1,NULL}"); err == nil {
+		t.Error("scanning a NULL int element succeeded, want an error")
+	}
+
+	var nilArray Array[int]
+	if value, err := nilArray.Value(); err != nil || value != nil {
+		t.Errorf("nil array value = %v, %v, want NULL", value, err)
+	}
+	if err := nilArray.Scan(nil); err != nil || nilArray != nil {
+		t.Errorf("scanned %v, %v from NULL, want a nil array", nilArray, err)
+	}
+}
+
+func TestDateArrayNullElements(t *testing.T) {
+	var dates Array[Date]
+	if err := dates.Scan(`{2024-05-06,NULL}`); err != nil {
+		t.Fatal(err)
+	}
+	if dates[0].String() != "2024-05-06" || !dates[1].IsZero() {
+		t.Fatalf("scanned %v, want a date and the zero Date", dates)
+	}
+	value, err := dates.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != `{"2024-05-06",NULL}` {
+		t.Errorf("value = %s, want the zero Date written as NULL", value)
+	}
+}
@@ -0,0 +1,245 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// NewDate returns the date for the given year, month and day.
+// Out of range values are normalized the same way time.Date does.
+func NewDate(year int, month time.Month, dd int) Date {
+	return Date{Time: time.Date(year, month, dd, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar date of t in its own location.
+func DateOf(t time.Time) Date {
+	return NewDate(t.Date())
+}
+
+// civil returns the date as a UTC midnight, dropping any clock component and location.
+func (n Date) civil() time.Time {
+	y, m, d := n.Time.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// AddDays returns the date n days later (or earlier if days is negative).
+func (n Date) AddDays(days int) Date {
+	return Date{Time: n.civil().AddDate(0, 0, days)}
+}
+
+// AddMonths returns the date n months later (or earlier if months is negative).
+// Unlike time.AddDate, the day is clamped to the end of the target month: Jan 31 + 1 month is Feb 28 (or 29).
+func (n Date) AddMonths(months int) Date {
+	y, m, d := n.Time.Date()
+	if last := daysIn(y, m+time.Month(months)); d > last {
+		d = last
+	}
+	return NewDate(y, m+time.Month(months), d)
+}
+
+// AddYears returns the date n years later (or earlier if years is negative), clamping Feb 29 to Feb 28.
+func (n Date) AddYears(years int) Date {
+	return n.AddMonths(12 * years)
+}
+
+// DaysBetween returns the number of days from n to other, negative if other is before n.
+func (n Date) DaysBetween(other Date) int {
+	return int(other.civil().Sub(n.civil()) / day)
+}
+
+// Weekday returns the day of the week of the date.
+func (n Date) Weekday() time.Weekday {
+	return n.civil().Weekday()
+}
+
+// ISOWeek returns the ISO 8601 year and week number of the date.
+func (n Date) ISOWeek() (year, week int) {
+	return n.civil().ISOWeek()
+}
+
+// StartOfMonth returns the first day of the month of the date.
+func (n Date) StartOfMonth() Date {
+	y, m, _ := n.Time.Date()
+	return NewDate(y, m, 1)
+}
+
+// EndOfMonth returns the last day of the month of the date.
+func (n Date) EndOfMonth() Date {
+	y, m, _ := n.Time.Date()
+	return NewDate(y, m, daysIn(y, m))
+}
+
+// CompareDate compares the calendar dates, ignoring any clock component and location.
+// It returns -1 if n is before other, 0 if they are the same day and +1 if n is after other.
+// It is not named Compare so as not to hide the method of the embedded time.Time.
+func (n Date) CompareDate(other Date) int {
+	return n.civil().Compare(other.civil())
+}
+
+// BeforeDate reports whether n is a day before other.
+func (n Date) BeforeDate(other Date) bool { return n.CompareDate(other) < 0 }
+
+// AfterDate reports whether n is a day after other.
+func (n Date) AfterDate(other Date) bool { return n.CompareDate(other) > 0 }
+
+// EqualDate reports whether n and other are the same day.
+func (n Date) EqualDate(other Date) bool { return n.CompareDate(other) == 0 }
+
+func daysIn(year int, month time.Month) int {
+	// Day 0 of the next month is normalized to the last day of this one.
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// NewTime returns the time of day for the given clock values.
+// Out of range values wrap around midnight.
+// Every Time is based on the date of the zero time.Time, so that midnight is the zero Time
+// and that times of day can be compared with ==.
+func NewTime(hour, minute, sec, nsec int) Time {
+	return Time{Time: time.Time{}.Add(wrapDay(time.Duration(hour)*time.Hour +
+		time.Duration(minute)*time.Minute +
+		time.Duration(sec)*time.Second +
+		time.Duration(nsec)))}
+}
+
+// timeOf returns the time of day of t.
+func timeOf(t time.Time) Time {
+	return NewTime(t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+}
+
+// sinceMidnight returns the clock component of the time as a duration.
+func (n Time) sinceMidnight() time.Duration {
+	h, m, s := n.Time.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(n.Time.Nanosecond())
+}
+
+// AddClock returns the time of day d later, wrapping around midnight.
+// It is not named Add so as not to hide the method of the embedded time.Time.
+func (n Time) AddClock(d time.Duration) Time {
+	return Time{Time: time.Time{}.Add(wrapDay(n.sinceMidnight() + d))}
+}
+
+// SubClock returns the duration n-other between two times of the same day.
+func (n Time) SubClock(other Time) time.Duration {
+	return n.sinceMidnight() - other.sinceMidnight()
+}
+
+// CompareClock compares the clock components of the times.
+// It returns -1 if n is before other, 0 if they are equal and +1 if n is after other.
+func (n Time) CompareClock(other Time) int {
+	switch a, b := n.sinceMidnight(), other.sinceMidnight(); {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// BeforeClock reports whether n is earlier in the day than other.
+func (n Time) BeforeClock(other Time) bool { return n.CompareClock(other) < 0 }
+
+// AfterClock reports whether n is later in the day than other.
+func (n Time) AfterClock(other Time) bool { return n.CompareClock(other) > 0 }
+
+// EqualClock reports whether n and other are the same time of day.
+func (n Time) EqualClock(other Time) bool { return n.CompareClock(other) == 0 }
+
+func wrapDay(d time.Duration) time.Duration {
+	if d %= day; d < 0 {
+		d += day
+	}
+	return d
+}
+
+// DSTGap defines how a wall clock time skipped by a DST transition is resolved.
+type DSTGap int
+
+const (
+	// DSTGapShiftForward shifts the time forward by the length of the gap (02:30 becomes 03:30).
+	DSTGapShiftForward DSTGap = iota
+	// DSTGapNextValid uses the first valid instant after the gap (02:30 becomes 03:00).
+	DSTGapNextValid
+	// DSTGapError returns ErrDSTGap.
+	DSTGapError
+)
+
+// DSTOverlap defines how a wall clock time occurring twice because of a DST transition is resolved.
+type DSTOverlap int
+
+const (
+	// DSTOverlapEarlier uses the first occurrence, before the clocks are turned back.
+	DSTOverlapEarlier DSTOverlap = iota
+	// DSTOverlapLater uses the second occurrence, after the clocks are turned back.
+	DSTOverlapLater
+	// DSTOverlapError returns ErrDSTOverlap.
+	DSTOverlapError
+)
+
+var (
+	// ErrDSTGap indicates that the wall clock time does not exist in the time zone.
+	ErrDSTGap = errors.New("wall clock time is skipped by a daylight saving time transition")
+	// ErrDSTOverlap indicates that the wall clock time is ambiguous in the time zone.
+	ErrDSTOverlap = errors.New("wall clock time is ambiguous because of a daylight saving time transition")
+)
+
+// At combines the date, the time of day and the time zone into an instant.
+// Gaps are shifted forward and overlaps resolve to the earlier instant.
+func (n Date) At(t Time, tz TimeZone) time.Time {
+	instant, _ := n.AtWithPolicy(t, tz, DSTGapShiftForward, DSTOverlapEarlier)
+	return instant
+}
+
+// AtWithPolicy combines the date, the time of day and the time zone into an instant,
+// resolving DST gaps and overlaps with the given policies.
+func (n Date) AtWithPolicy(t Time, tz TimeZone, gap DSTGap, overlap DSTOverlap) (time.Time, error) {
+	loc := &tz.Location
+	wall := n.civil().Add(t.sinceMidnight())
+
+	// We assume at most one transition a day around the wall clock time.
+	_, before := wall.Add(-day).In(loc).Zone()
+	_, after := wall.Add(day).In(loc).Zone()
+
+	var candidates []time.Time
+	for _, offset := range []int{before, after} {
+		instant := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, actual := instant.Zone(); actual == offset {
+			if len(candidates) == 0 || !candidates[0].Equal(instant) {
+				candidates = append(candidates, instant)
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 2:
+		switch overlap {
+		case DSTOverlapLater:
+			if candidates[1].Before(candidates[0]) {
+				return candidates[0], nil
+			}
+			return candidates[1], nil
+		case DSTOverlapError:
+			return time.Time{}, ErrDSTOverlap
+		default:
+			if candidates[1].Before(candidates[0]) {
+				return candidates[1], nil
+			}
+			return candidates[0], nil
+		}
+	}
+
+	// Using the offset in effect before the gap lands after the transition, shifted by the gap length.
+	shifted := wall.Add(-time.Duration(before) * time.Second).In(loc)
+	switch gap {
+	case DSTGapNextValid:
+		start, _ := shifted.ZoneBounds()
+		return start, nil
+	case DSTGapError:
+		return time.Time{}, ErrDSTGap
+	default:
+		return shifted, nil
+	}
+}
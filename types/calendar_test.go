@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeConstructorsAgree(t *testing.T) {
+	parsed, err := ParseTime("13:45:30.5", ParseStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned Time
+	if err := scanned.Scan(time.Date(2024, 5, 6, 13, 45, 30, 500000000, time.FixedZone("", 3600))); err != nil {
+		t.Fatal(err)
+	}
+	built := NewTime(13, 45, 30, 500000000)
+	if parsed != built || scanned != built {
+		t.Errorf("ParseTime = %#v, Scan = %#v, NewTime = %#v, want them equal", parsed.Time, scanned.Time, built.Time)
+	}
+}
+
+func TestTimeMidnightIsZero(t *testing.T) {
+	parsed, err := ParseTime("00:00:00", ParseStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != (Time{}) || NewTime(0, 0, 0, 0) != (Time{}) {
+		t.Errorf("ParseTime(%q) = %#v, want the zero Time", "00:00:00", parsed.Time)
+	}
+	if !parsed.IsZero() {
+		t.Errorf("ParseTime(%q).IsZero() = false, want true", "00:00:00")
+	}
+}
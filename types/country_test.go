@@ -0,0 +1,50 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCountryUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    Country
+		wantErr bool
+	}{
+		{`"FR"`, "FR", false},
+		{`""`, "", false},
+		{`null`, "", false},
+		{`"FRA"`, "", true},
+		{`"fr"`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			n := Country("DE")
+			err := json.Unmarshal([]byte(tt.data), &n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && n != tt.want {
+				t.Errorf("country = %q, want %q", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionalCountryAndLanguageJSONRoundTrip(t *testing.T) {
+	type address struct {
+		Country  Country  `json:"country"`
+		Language Language `json:"language"`
+	}
+	data, err := json.Marshal(address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got address
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshalling %s: %v", data, err)
+	}
+	if got != (address{}) {
+		t.Errorf("address = %+v, want the zero value", got)
+	}
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCurrencyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    Currency
+		wantErr bool
+	}{
+		{`"EUR"`, "EUR", false},
+		{`""`, "", false},
+		{`null`, "", false},
+		{`"EURO"`, "", true},
+		{`"eur"`, "", true},
+		{`42`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			n := Currency("USD")
+			err := json.Unmarshal([]byte(tt.data), &n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && n != tt.want {
+				t.Errorf("currency = %q, want %q", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestZeroMoneyJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(Money{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m Money
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("unmarshalling %s: %v", data, err)
+	}
+	if !m.IsZero() || m.Currency != "" {
+		t.Errorf("money = %v, want the zero value", m)
+	}
+}
@@ -0,0 +1,234 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an arbitrary-precision decimal number formatted as a Postgres numeric.
+// The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	// The value is unscaled * 10^-scale.
+	scale int32
+}
+
+// maxDecimalExponent bounds exponents to the Postgres numeric range so that parsing stays cheap.
+const maxDecimalExponent = 16383
+
+// NewDecimal returns unscaled * 10^-scale.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}.normalize()
+}
+
+// ParseDecimal parses a decimal number such as "-12.30" or "1.5e3".
+func ParseDecimal(s string) (Decimal, error) {
+	str := s
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil || e > maxDecimalExponent || e < -maxDecimalExponent {
+			return Decimal{}, &ParseError{Type: "decimal", Value: s, Reason: "invalid exponent"}
+		}
+		exp, str = e, str[:i]
+	}
+	integer, fraction, _ := strings.Cut(str, ".")
+	digits := strings.TrimLeft(integer, "+-")
+	if len(integer)-len(digits) > 1 || digits+fraction == "" || strings.ContainsAny(digits+fraction, "+-") {
+		return Decimal{}, &ParseError{Type: "decimal", Value: s, Reason: "expected a decimal number"}
+	}
+	unscaled, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return Decimal{}, &ParseError{Type: "decimal", Value: s, Reason: "expected a decimal number"}
+	}
+	return Decimal{unscaled: unscaled, scale: int32(int64(len(fraction)) - exp)}.normalize(), nil
+}
+
+// normalize ensures the scale is positive so that the value can be printed without exponent.
+func (n Decimal) normalize() Decimal {
+	if n.scale < 0 {
+		n.unscaled = new(big.Int).Mul(n.int(), pow10(-n.scale))
+		n.scale = 0
+	}
+	return n
+}
+
+func (n Decimal) int() *big.Int {
+	if n.unscaled == nil {
+		return new(big.Int)
+	}
+	return n.unscaled
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the unscaled value of n at a greater scale.
+func (n Decimal) rescale(scale int32) *big.Int {
+	if scale == n.scale {
+		return n.int()
+	}
+	return new(big.Int).Mul(n.int(), pow10(scale-n.scale))
+}
+
+// Scale returns the number of digits after the decimal point.
+func (n Decimal) Scale() int32 {
+	return n.scale
+}
+
+// String returns the decimal formatted without exponent, keeping trailing zeros.
+func (n Decimal) String() string {
+	digits := new(big.Int).Abs(n.int()).String()
+	if n.scale > 0 {
+		if pad := int(n.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(n.scale)] + "." + digits[len(digits)-int(n.scale):]
+	}
+	if n.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the decimal.
+func (n Decimal) Sign() int {
+	return n.int().Sign()
+}
+
+// IsZero reports whether the decimal is 0.
+func (n Decimal) IsZero() bool {
+	return n.Sign() == 0
+}
+
+// Cmp returns -1 if n < other, 0 if n == other and +1 if n > other.
+func (n Decimal) Cmp(other Decimal) int {
+	scale := max(n.scale, other.scale)
+	return n.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Add returns n + other.
+func (n Decimal) Add(other Decimal) Decimal {
+	scale := max(n.scale, other.scale)
+	return Decimal{unscaled: new(big.Int).Add(n.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub returns n - other.
+func (n Decimal) Sub(other Decimal) Decimal {
+	return n.Add(other.Neg())
+}
+
+// Mul returns n * other.
+func (n Decimal) Mul(other Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(n.int(), other.int()), scale: n.scale + other.scale}
+}
+
+// Neg returns -n.
+func (n Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(n.int()), scale: n.scale}
+}
+
+// RoundingMode defines how a decimal is rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest neighbor, ties to the even one (banker's rounding).
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest neighbor, ties away from zero.
+	RoundHalfUp
+	// RoundDown rounds towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Round returns the decimal rounded to the given number of digits after the decimal point.
+func (n Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if n.scale <= places {
+		return Decimal{unscaled: n.rescale(places), scale: places}
+	}
+	divisor := pow10(n.scale - places)
+	q, r := new(big.Int).QuoRem(n.int(), divisor, new(big.Int))
+	if r.Sign() != 0 {
+		half := new(big.Int).Abs(r)
+		half.Mul(half, big.NewInt(2))
+		var away bool
+		switch mode {
+		case RoundHalfUp:
+			away = half.Cmp(divisor) >= 0
+		case RoundDown:
+			away = false
+		case RoundUp:
+			away = true
+		default:
+			cmp := half.Cmp(divisor)
+			away = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+		}
+		if away {
+			q.Add(q, big.NewInt(int64(n.Sign())))
+		}
+	}
+	return Decimal{unscaled: q, scale: places}
+}
+
+// MarshalJSON marshals the decimal as a string to preserve its precision.
+func (n Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.String())
+}
+
+// UnmarshalJSON unmarshals the decimal from a string or a JSON number.
+func (n *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Decimal) Value() (driver.Value, error) {
+	return n.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Decimal) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = Decimal{}
+	case string, []byte:
+		parsed, err := ParseDecimal(asString(t))
+		if err != nil {
+			return err
+		}
+		*n = parsed
+	case int64:
+		*n = NewDecimal(t, 0)
+	case float64:
+		parsed, err := ParseDecimal(strconv.FormatFloat(t, 'f', -1, 64))
+		if err != nil {
+			return err
+		}
+		*n = parsed
+	default:
+		return fmt.Errorf("incompatible type %T for Decimal", value)
+	}
+	return nil
+}
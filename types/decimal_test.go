@@ -0,0 +1,138 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{"12.30", "12.30", false},
+		{"-12.30", "-12.30", false},
+		{"+5", "5", false},
+		{"0", "0", false},
+		{"-0.00", "0.00", false},
+		{".5", "0.5", false},
+		{"5.", "5", false},
+		{"0.001", "0.001", false},
+		{"1.5e3", "1500", false},
+		{"1.5E-3", "0.0015", false},
+		{"-25e-1", "-2.5", false},
+		{"", "", true},
+		{".", "", true},
+		{"abc", "", true},
+		{"1,5", "", true},
+		{"--1", "", true},
+		{"+-1", "", true},
+		{"1-2", "", true},
+		{"1.-2", "", true},
+		{"1.2.3", "", true},
+		{"1e", "", true},
+		{"e5", "", true},
+		{"1e1.5", "", true},
+		{"1e99999", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseDecimal(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("ParseDecimal(%q) = %s, want %s", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		value  string
+		places int32
+		mode   RoundingMode
+		want   string
+	}{
+		{"2.345", 2, RoundHalfEven, "2.34"},
+		{"2.355", 2, RoundHalfEven, "2.36"},
+		{"2.3451", 2, RoundHalfEven, "2.35"},
+		{"2.344", 2, RoundHalfEven, "2.34"},
+		{"-2.345", 2, RoundHalfEven, "-2.34"},
+		{"-2.355", 2, RoundHalfEven, "-2.36"},
+		{"0.5", 0, RoundHalfEven, "0"},
+		{"1.5", 0, RoundHalfEven, "2"},
+		{"2.345", 2, RoundHalfUp, "2.35"},
+		{"2.344", 2, RoundHalfUp, "2.34"},
+		{"-2.345", 2, RoundHalfUp, "-2.35"},
+		{"-2.344", 2, RoundHalfUp, "-2.34"},
+		{"2.349", 2, RoundDown, "2.34"},
+		{"-2.349", 2, RoundDown, "-2.34"},
+		{"2.341", 2, RoundUp, "2.35"},
+		{"-2.341", 2, RoundUp, "-2.35"},
+		{"2.340", 2, RoundUp, "2.34"},
+		{"-0.001", 2, RoundUp, "-0.01"},
+		{"-0.001", 2, RoundDown, "0.00"},
+		{"2.5", 2, RoundHalfEven, "2.50"},
+		{"12", 2, RoundHalfUp, "12.00"},
+		{"2.5", -1, RoundHalfUp, "3"},
+	}
+	for _, tt := range tests {
+		got := mustDecimal(t, tt.value).Round(tt.places, tt.mode)
+		if got.String() != tt.want {
+			t.Errorf("Round(%s, %d, %d) = %s, want %s", tt.value, tt.places, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := mustDecimal(t, "0.1"), mustDecimal(t, "0.25")
+	if got := a.Add(b).String(); got != "0.35" {
+		t.Errorf("0.1 + 0.25 = %s, want 0.35", got)
+	}
+	if got := a.Sub(b).String(); got != "-0.15" {
+		t.Errorf("0.1 - 0.25 = %s, want -0.15", got)
+	}
+	if got := a.Mul(b).String(); got != "0.025" {
+		t.Errorf("0.1 * 0.25 = %s, want 0.025", got)
+	}
+	if got := (Decimal{}).Add(b).String(); got != "0.25" {
+		t.Errorf("0 + 0.25 = %s, want 0.25", got)
+	}
+	if cmp := mustDecimal(t, "1.50").Cmp(mustDecimal(t, "1.5")); cmp != 0 {
+		t.Errorf("Cmp(1.50, 1.5) = %d, want 0", cmp)
+	}
+	if cmp := mustDecimal(t, "-2").Cmp(mustDecimal(t, "1.5")); cmp != -1 {
+		t.Errorf("Cmp(-2, 1.5) = %d, want -1", cmp)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	for data, want := range map[string]string{`"12.30"`: "12.30", `12.30`: "12.30", `-1e2`: "-100"} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			t.Fatalf("unmarshalling %s: %v", data, err)
+		}
+		if d.String() != want {
+			t.Errorf("unmarshalling %s = %s, want %s", data, d, want)
+		}
+	}
+	data, err := json.Marshal(mustDecimal(t, "12.30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"12.30"` {
+		t.Errorf("marshalled %s, want \"12.30\"", data)
+	}
+}
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (n Time) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (n *Time) UnmarshalText(data []byte) error {
+	parsed, err := ParseTime(string(data), ParseDefault)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (n Time) MarshalBinary() ([]byte, error) {
+	return n.MarshalText()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (n *Time) UnmarshalBinary(data []byte) error {
+	return n.UnmarshalText(data)
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (n Time) GobEncode() ([]byte, error) {
+	return n.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (n *Time) GobDecode(data []byte) error {
+	return n.UnmarshalBinary(data)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (n Time) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(n.String(), start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+func (n *Time) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+func (n Time) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: n.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (n *Time) UnmarshalXMLAttr(attr xml.Attr) error {
+	return n.UnmarshalText([]byte(attr.Value))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (n Date) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (n *Date) UnmarshalText(data []byte) error {
+	parsed, err := ParseDate(string(data), ParseDefault)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (n Date) MarshalBinary() ([]byte, error) {
+	return n.MarshalText()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (n *Date) UnmarshalBinary(data []byte) error {
+	return n.UnmarshalText(data)
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (n Date) GobEncode() ([]byte, error) {
+	return n.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (n *Date) GobDecode(data []byte) error {
+	return n.UnmarshalBinary(data)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (n Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(n.String(), start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+func (n *Date) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+func (n Date) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: n.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (n *Date) UnmarshalXMLAttr(attr xml.Attr) error {
+	return n.UnmarshalText([]byte(attr.Value))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (n TimeZone) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (n *TimeZone) UnmarshalText(data []byte) error {
+	parsed, err := time.LoadLocation(string(data))
+	if err != nil {
+		return err
+	}
+	*n = TimeZone{Location: *parsed}
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (n TimeZone) MarshalBinary() ([]byte, error) {
+	return n.MarshalText()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (n *TimeZone) UnmarshalBinary(data []byte) error {
+	return n.UnmarshalText(data)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (n TimeZone) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(n.String(), start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+func (n *TimeZone) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+func (n TimeZone) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: n.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (n *TimeZone) UnmarshalXMLAttr(attr xml.Attr) error {
+	return n.UnmarshalText([]byte(attr.Value))
+}
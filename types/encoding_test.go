@@ -0,0 +1,40 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestGobRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		out  any
+	}{
+		{"zero time", Time{}, new(Time)},
+		{"time", NewTime(13, 45, 30, 500), new(Time)},
+		{"date", NewDate(2024, 2, 29), new(Date)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(tt.in); err != nil {
+				t.Fatal(err)
+			}
+			if err := gob.NewDecoder(&buf).Decode(tt.out); err != nil {
+				t.Fatal(err)
+			}
+			var got any
+			switch out := tt.out.(type) {
+			case *Time:
+				got = *out
+			case *Date:
+				got = *out
+			}
+			if got != tt.in {
+				t.Errorf("round trip of %v = %v, want it unchanged", tt.in, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package types
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Keyring provides the AES keys used by Encrypted values.
+type Keyring interface {
+	// PrimaryKey returns the key used to encrypt new values and its ID.
+	PrimaryKey() (id string, key []byte, err error)
+	// Key returns the key matching an ID embedded in a ciphertext.
+	Key(id string) ([]byte, error)
+}
+
+// DefaultKeyring is the keyring used by Encrypted values.
+var DefaultKeyring Keyring
+
+// ErrNoKeyring indicates that DefaultKeyring is not set.
+var ErrNoKeyring = errors.New("no keyring configured for encrypted values")
+
+// NewKeyring returns a static keyring encrypting with the primary key and decrypting with any of the keys.
+// Keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+// Keys are rotated by adding a new key, making it the primary and reencrypting the values before removing the old one.
+func NewKeyring(primary string, keys map[string][]byte) (Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q not found", primary)
+	}
+	for id, key := range keys {
+		if len(id) == 0 || len(id) > 255 {
+			return nil, fmt.Errorf("key id %q must be between 1 and 255 bytes long", id)
+		}
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+	}
+	return &staticKeyring{primary: primary, keys: keys}, nil
+}
+
+type staticKeyring struct {
+	primary string
+	keys    map[string][]byte
+}
+
+func (k *staticKeyring) PrimaryKey() (string, []byte, error) {
+	return k.primary, k.keys[k.primary], nil
+}
+
+func (k *staticKeyring) Key(id string) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found", id)
+	}
+	return key, nil
+}
+
+// encryptedVersion prefixes ciphertexts so that the format can evolve.
+const encryptedVersion = 1
+
+// Encrypted is a value encrypted at rest with AES-GCM, that is NULL when not valid.
+// It is stored as base64 text, which fits both text and bytea columns, and embeds the ID of its key
+// so that values encrypted with older keys can still be read.
+// The value is marshaled in plaintext to JSON but redacted when printed, which keeps it out of logfmt logs.
+// JSON loggers use MarshalJSON, so they should rely on Redacted instead.
+type Encrypted[T any] struct {
+	Val   T
+	Valid bool
+}
+
+// NewEncrypted returns a valid encrypted value holding v.
+func NewEncrypted[T any](v T) Encrypted[T] {
+	return Encrypted[T]{Val: v, Valid: true}
+}
+
+// String redacts the value.
+func (n Encrypted[T]) String() string {
+	return "[REDACTED]"
+}
+
+// Redacted returns the value to log in place of the plaintext.
+func (n Encrypted[T]) Redacted() any {
+	return n.String()
+}
+
+// GoString redacts the value.
+func (n Encrypted[T]) GoString() string {
+	return n.String()
+}
+
+// Format redacts the value, whatever the verb.
+func (n Encrypted[T]) Format(f fmt.State, verb rune) {
+	f.Write([]byte(n.String()))
+}
+
+// MarshalJSON marshals the plaintext value, or null when not valid.
+func (n Encrypted[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Val)
+}
+
+// UnmarshalJSON unmarshals the plaintext value, null making it not valid.
+func (n *Encrypted[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = Encrypted[T]{}
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = Encrypted[T]{Val: v, Valid: true}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Encrypted[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if DefaultKeyring == nil {
+		return nil, ErrNoKeyring
+	}
+	id, key, err := DefaultKeyring.PrimaryKey()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(n.Val)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte{encryptedVersion, byte(len(id))}, id...)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// The header is authenticated so that the key ID cannot be tampered with.
+	ciphertext := gcm.Seal(append(header, nonce...), nonce, plaintext, header)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Encrypted[T]) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = Encrypted[T]{}
+		return nil
+	case string, []byte:
+		data, err := base64.StdEncoding.DecodeString(asString(t))
+		if err != nil {
+			return errors.New("encrypted value must be base64 encoded")
+		}
+		plaintext, err := decrypt(data)
+		if err != nil {
+			return err
+		}
+		var v T
+		if err := json.Unmarshal(plaintext, &v); err != nil {
+			return err
+		}
+		*n = Encrypted[T]{Val: v, Valid: true}
+		return nil
+	default:
+		return fmt.Errorf("incompatible type %T for Encrypted", value)
+	}
+}
+
+func decrypt(data []byte) ([]byte, error) {
+	if DefaultKeyring == nil {
+		return nil, ErrNoKeyring
+	}
+	if len(data) < 2 || data[0] != encryptedVersion || len(data) < 2+int(data[1]) {
+		return nil, errors.New("invalid encrypted value header")
+	}
+	header, rest := data[:2+int(data[1])], data[2+int(data[1]):]
+	key, err := DefaultKeyring.Key(string(header[2:]))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted value nonce")
+	}
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, errors.New("could not decrypt value")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
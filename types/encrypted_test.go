@@ -0,0 +1,142 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+var (
+	testKey1 = bytes.Repeat([]byte{1}, 32)
+	testKey2 = bytes.Repeat([]byte{2}, 16)
+)
+
+func setKeyring(t *testing.T, primary string, keys map[string][]byte) {
+	t.Helper()
+	keyring, err := NewKeyring(primary, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := DefaultKeyring
+	DefaultKeyring = keyring
+	t.Cleanup(func() { DefaultKeyring = previous })
+}
+
+func encrypt(t *testing.T, v string) string {
+	t.Helper()
+	value, err := NewEncrypted(v).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value.(string)
+}
+
+func decryptString(s string) (string, error) {
+	var n Encrypted[string]
+	err := n.Scan(s)
+	return n.Val, err
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	setKeyring(t, "k1", map[string][]byte{"k1": testKey1})
+	ciphertext := encrypt(t, "s3cr3t")
+	if bytes.Contains([]byte(ciphertext), []byte("s3cr3t")) {
+		t.Fatalf("ciphertext %s contains the plaintext", ciphertext)
+	}
+	if other := encrypt(t, "s3cr3t"); other == ciphertext {
+		t.Error("encrypting twice gave the same ciphertext, want a random nonce")
+	}
+	if got, err := decryptString(ciphertext); err != nil || got != "s3cr3t" {
+		t.Errorf("decrypted %q, %v, want s3cr3t", got, err)
+	}
+
+	var n Encrypted[string]
+	if err := n.Scan([]byte(ciphertext)); err != nil || !n.Valid || n.Val != "s3cr3t" {
+		t.Errorf("scanned %+v, %v from bytes, want s3cr3t", n.Val, err)
+	}
+	if value, err := (Encrypted[string]{}).Value(); err != nil || value != nil {
+		t.Errorf("invalid value = %v, %v, want NULL", value, err)
+	}
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("scanned %+v, %v from NULL, want an invalid value", n, err)
+	}
+	if s := fmt.Sprintf("%v %+v %#v %s", n, NewEncrypted("s3cr3t"), NewEncrypted("s3cr3t"), NewEncrypted("s3cr3t")); bytes.Contains([]byte(s), []byte("s3cr3t")) {
+		t.Errorf("formatted %q, want the value redacted", s)
+	}
+}
+
+func TestEncryptedKeyRotation(t *testing.T) {
+	setKeyring(t, "k1", map[string][]byte{"k1": testKey1})
+	old := encrypt(t, "old")
+
+	setKeyring(t, "k2", map[string][]byte{"k1": testKey1, "k2": testKey2})
+	if got, err := decryptString(old); err != nil || got != "old" {
+		t.Errorf("decrypted %q, %v with the rotated keyring, want old", got, err)
+	}
+	rotated := encrypt(t, "new")
+	if data, _ := base64.StdEncoding.DecodeString(rotated); string(data[2:4]) != "k2" {
+		t.Errorf("new value header = %q, want the k2 key ID", data[:4])
+	}
+
+	setKeyring(t, "k2", map[string][]byte{"k2": testKey2})
+	if _, err := decryptString(old); err == nil {
+		t.Error("decrypted a value whose key was removed, want an error")
+	}
+	if got, err := decryptString(rotated); err != nil || got != "new" {
+		t.Errorf("decrypted %q, %v, want new", got, err)
+	}
+}
+
+func TestEncryptedTampering(t *testing.T) {
+	// Both keys share the same material, so only the authenticated header tells them apart.
+	setKeyring(t, "k1", map[string][]byte{"k1": testKey1, "k2": testKey1})
+	data, err := base64.StdEncoding.DecodeString(encrypt(t, "s3cr3t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamper := func(f func(data []byte) []byte) string {
+		return base64.StdEncoding.EncodeToString(f(bytes.Clone(data)))
+	}
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "not base64!"},
+		{"empty", ""},
+		{"bad version", tamper(func(d []byte) []byte { d[0] = 2; return d })},
+		{"key ID longer than the value", tamper(func(d []byte) []byte { d[1] = 255; return d })},
+		{"unknown key ID", tamper(func(d []byte) []byte { d[3] = '9'; return d })},
+		{"changed key ID byte", tamper(func(d []byte) []byte { d[3] = '2'; return d })},
+		{"truncated nonce", tamper(func(d []byte) []byte { return d[:10] })},
+		{"changed ciphertext", tamper(func(d []byte) []byte { d[len(d)-1] ^= 1; return d })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := decryptString(tt.value); err == nil {
+				t.Errorf("decrypted %q, want an error", got)
+			}
+		})
+	}
+}
+
+func TestEncryptedWithoutKeyring(t *testing.T) {
+	previous := DefaultKeyring
+	DefaultKeyring = nil
+	defer func() { DefaultKeyring = previous }()
+	if _, err := NewEncrypted("s3cr3t").Value(); err != ErrNoKeyring {
+		t.Errorf("Value error = %v, want ErrNoKeyring", err)
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	if _, err := NewKeyring("k3", map[string][]byte{"k1": testKey1}); err == nil {
+		t.Error("NewKeyring with an unknown primary key succeeded")
+	}
+	if _, err := NewKeyring("k1", map[string][]byte{"k1": []byte("short")}); err == nil {
+		t.Error("NewKeyring with an invalid AES key succeeded")
+	}
+	if _, err := NewKeyring("", map[string][]byte{"": testKey1}); err == nil {
+		t.Error("NewKeyring with an empty key ID succeeded")
+	}
+}
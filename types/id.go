@@ -0,0 +1,168 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// IDEncoding defines how the UUID of a prefixed ID is encoded.
+type IDEncoding int
+
+const (
+	// IDBase62 encodes the UUID as 22 base62 characters.
+	IDBase62 IDEncoding = iota
+	// IDBase32 encodes the UUID as 26 lowercase Crockford base32 characters.
+	IDBase32
+)
+
+// IDKind defines the prefix of a kind of ID, such as "usr" for "usr_2x4...".
+// It may also implement Encoding() IDEncoding to use another encoding than IDBase62.
+type IDKind interface {
+	Prefix() string
+}
+
+type idEncoder interface {
+	Encoding() IDEncoding
+}
+
+// ID is a Stripe-style prefixed ID backed by a UUID, stored as a Postgres uuid and NULL when set to its zero.
+//
+//	type user struct{}
+//	func (user) Prefix() string { return "usr" }
+//	type UserID = types.ID[user]
+type ID[K IDKind] struct {
+	UUID UUID
+}
+
+// NewID returns a new time-ordered ID.
+func NewID[K IDKind]() ID[K] {
+	return ID[K]{UUID: NewUUIDv7()}
+}
+
+// ParseID parses a prefixed ID, ensuring its prefix matches its kind.
+func ParseID[K IDKind](s string) (ID[K], error) {
+	var kind K
+	// The encodings never contain underscores, but prefixes may.
+	i := strings.LastIndex(s, "_")
+	if i < 0 || s[:i] != kind.Prefix() {
+		return ID[K]{}, &ParseError{Type: "id", Value: s, Reason: fmt.Sprintf("expected the prefix %q", kind.Prefix()+"_")}
+	}
+	n, ok := decodeID(s[i+1:], idEncoding(kind))
+	if !ok {
+		return ID[K]{}, &ParseError{Type: "id", Value: s, Reason: "invalid encoding"}
+	}
+	var id ID[K]
+	n.FillBytes(id.UUID.UUID[:])
+	return id, nil
+}
+
+func idEncoding(kind IDKind) IDEncoding {
+	if e, ok := kind.(idEncoder); ok {
+		return e.Encoding()
+	}
+	return IDBase62
+}
+
+const crockfordAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+func encodeID(n *big.Int, encoding IDEncoding) string {
+	if encoding == IDBase32 {
+		b := make([]byte, 26)
+		for i := len(b) - 1; i >= 0; i-- {
+			b[i] = crockfordAlphabet[new(big.Int).And(n, big.NewInt(31)).Int64()]
+			n = new(big.Int).Rsh(n, 5)
+		}
+		return string(b)
+	}
+	s := n.Text(62)
+	return strings.Repeat("0", 22-len(s)) + s
+}
+
+func decodeID(s string, encoding IDEncoding) (*big.Int, bool) {
+	n := new(big.Int)
+	if encoding == IDBase32 {
+		if len(s) != 26 {
+			return nil, false
+		}
+		for _, c := range strings.ToLower(s) {
+			i := strings.IndexRune(crockfordAlphabet, c)
+			if i < 0 {
+				return nil, false
+			}
+			n.Lsh(n, 5).Or(n, big.NewInt(int64(i)))
+		}
+	} else {
+		// SetString accepts a sign, which would give several text forms to the same ID.
+		if len(s) != 22 || s[0] == '-' || s[0] == '+' {
+			return nil, false
+		}
+		if _, ok := n.SetString(s, 62); !ok {
+			return nil, false
+		}
+	}
+	return n, n.BitLen() <= 128
+}
+
+// IsZero reports whether the ID is its zero value.
+func (n ID[K]) IsZero() bool {
+	return n.UUID.IsZero()
+}
+
+// String returns the prefixed ID, or an empty string when zero.
+func (n ID[K]) String() string {
+	if n.IsZero() {
+		return ""
+	}
+	var kind K
+	return kind.Prefix() + "_" + encodeID(new(big.Int).SetBytes(n.UUID.UUID[:]), idEncoding(kind))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (n ID[K]) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (n *ID[K]) UnmarshalText(data []byte) error {
+	parsed, err := ParseID[K](string(data))
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// MarshalJSON marshals the ID as a prefixed string, or null when zero.
+func (n ID[K]) MarshalJSON() ([]byte, error) {
+	if n.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String())
+}
+
+// UnmarshalJSON unmarshals the ID from a prefixed string, ensuring its prefix matches its kind.
+func (n *ID[K]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = ID[K]{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(s))
+}
+
+// Value implements the driver.Valuer interface.
+func (n ID[K]) Value() (driver.Value, error) {
+	return n.UUID.Value()
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *ID[K]) Scan(value any) error {
+	return n.UUID.Scan(value)
+}
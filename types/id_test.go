@@ -0,0 +1,111 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testUser struct{}
+
+func (testUser) Prefix() string { return "usr" }
+
+type testOrder struct{}
+
+func (testOrder) Prefix() string       { return "ord_line" }
+func (testOrder) Encoding() IDEncoding { return IDBase32 }
+
+func mustUUID(t *testing.T, s string) UUID {
+	t.Helper()
+	u, err := ParseUUID(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestIDEncoding(t *testing.T) {
+	tests := []struct {
+		uuid   string
+		base62 string
+		base32 string
+	}{
+		{"00000000-0000-0000-0000-000000000001", "usr_0000000000000000000001", "ord_line_00000000000000000000000001"},
+		{"ffffffff-ffff-ffff-ffff-ffffffffffff", "usr_7N42dgm5tFLK9N8MT7fHC7", "ord_line_7zzzzzzzzzzzzzzzzzzzzzzzzz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uuid, func(t *testing.T) {
+			u := mustUUID(t, tt.uuid)
+			if got := (ID[testUser]{UUID: u}).String(); got != tt.base62 {
+				t.Errorf("base62 = %s, want %s", got, tt.base62)
+			}
+			if got := (ID[testOrder]{UUID: u}).String(); got != tt.base32 {
+				t.Errorf("base32 = %s, want %s", got, tt.base32)
+			}
+			if id, err := ParseID[testUser](tt.base62); err != nil || id.UUID != u {
+				t.Errorf("ParseID(%s) = %v, %v, want %s", tt.base62, id.UUID, err, tt.uuid)
+			}
+			if id, err := ParseID[testOrder](tt.base32); err != nil || id.UUID != u {
+				t.Errorf("ParseID(%s) = %v, %v, want %s", tt.base32, id.UUID, err, tt.uuid)
+			}
+		})
+	}
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	for range 100 {
+		user := NewID[testUser]()
+		if parsed, err := ParseID[testUser](user.String()); err != nil || parsed != user {
+			t.Fatalf("ParseID(%s) = %v, %v, want the same ID", user, parsed, err)
+		}
+		order := ID[testOrder](user)
+		if parsed, err := ParseID[testOrder](order.String()); err != nil || parsed != order {
+			t.Fatalf("ParseID(%s) = %v, %v, want the same ID", order, parsed, err)
+		}
+	}
+}
+
+func TestParseIDInvalid(t *testing.T) {
+	s := NewID[testUser]().String()
+	base32 := ID[testOrder](NewID[testUser]()).String()
+	tests := []struct {
+		name  string
+		parse func() error
+	}{
+		{"wrong prefix", func() error { _, err := ParseID[testUser]("org_" + s[4:]); return err }},
+		{"no prefix", func() error { _, err := ParseID[testUser](s[4:]); return err }},
+		{"short", func() error { _, err := ParseID[testUser](s[:len(s)-1]); return err }},
+		{"long", func() error { _, err := ParseID[testUser](s + "0"); return err }},
+		{"minus sign", func() error { _, err := ParseID[testUser]("usr_-" + s[5:]); return err }},
+		{"plus sign", func() error { _, err := ParseID[testUser]("usr_+" + s[5:]); return err }},
+		{"invalid character", func() error { _, err := ParseID[testUser]("usr_!" + s[5:]); return err }},
+		{"base62 overflow", func() error { _, err := ParseID[testUser]("usr_" + strings.Repeat("Z", 22)); return err }},
+		{"base32 excluded letter", func() error { _, err := ParseID[testOrder]("ord_line_u" + base32[10:]); return err }},
+		{"base32 overflow", func() error { _, err := ParseID[testOrder]("ord_line_" + strings.Repeat("z", 26)); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.parse(); err == nil {
+				t.Error("ParseID succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestIDJSON(t *testing.T) {
+	id := NewID[testUser]()
+	data, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed ID[testUser]
+	if err := json.Unmarshal(data, &parsed); err != nil || parsed != id {
+		t.Errorf("unmarshalling %s = %v, %v, want the same ID", data, parsed, err)
+	}
+	if data, _ := json.Marshal(ID[testUser]{}); string(data) != "null" {
+		t.Errorf("zero ID marshalled as %s, want null", data)
+	}
+	if err := json.Unmarshal([]byte("null"), &parsed); err != nil || !parsed.IsZero() {
+		t.Errorf("unmarshalling null = %v, %v, want the zero ID", parsed, err)
+	}
+}
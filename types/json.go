@@ -0,0 +1,117 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON is a Postgres json or jsonb value holding a T, that is NULL when not valid.
+type JSON[T any] struct {
+	Val   T
+	Valid bool
+}
+
+// NewJSON returns a valid JSON value holding v.
+func NewJSON[T any](v T) JSON[T] {
+	return JSON[T]{Val: v, Valid: true}
+}
+
+// MarshalJSON marshals the held value, or null when not valid.
+func (n JSON[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Val)
+}
+
+// UnmarshalJSON unmarshals the held value, null making it not valid.
+func (n *JSON[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = JSON[T]{}
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = JSON[T]{Val: v, Valid: true}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n JSON[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	b, err := json.Marshal(n.Val)
+	if err != nil {
+		return nil, err
+	}
+	// Drivers may send []byte as bytea, which Postgres refuses for json columns.
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *JSON[T]) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = JSON[T]{}
+	case string, []byte:
+		var v T
+		if err := json.Unmarshal([]byte(asString(t)), &v); err != nil {
+			return err
+		}
+		*n = JSON[T]{Val: v, Valid: true}
+	default:
+		return fmt.Errorf("incompatible type %T for JSON", value)
+	}
+	return nil
+}
+
+// JSONRaw is a raw Postgres json or jsonb value that is NULL when empty.
+type JSONRaw []byte
+
+// MarshalJSON returns the raw value, or null when empty.
+func (n JSONRaw) MarshalJSON() ([]byte, error) {
+	if len(n) == 0 {
+		return []byte("null"), nil
+	}
+	return n, nil
+}
+
+// UnmarshalJSON copies the raw value.
+func (n *JSONRaw) UnmarshalJSON(data []byte) error {
+	if n == nil {
+		return errors.New("JSONRaw: UnmarshalJSON on nil pointer")
+	}
+	*n = append((*n)[:0], data...)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n JSONRaw) Value() (driver.Value, error) {
+	if len(n) == 0 {
+		return nil, nil
+	}
+	if !json.Valid(n) {
+		return nil, errors.New("invalid JSON for JSONRaw")
+	}
+	return string(n), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *JSONRaw) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = nil
+	case string, []byte:
+		// Drivers may reuse the scanned buffer, so we copy it.
+		*n = JSONRaw(asString(t))
+	default:
+		return fmt.Errorf("incompatible type %T for JSONRaw", value)
+	}
+	return nil
+}
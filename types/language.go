@@ -0,0 +1,104 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Language is a canonical BCP 47 language tag that is NULL when empty.
+type Language string
+
+// ParseLanguage parses a BCP 47 language tag and returns its canonical form ("en-us" becomes "en-US", "iw" becomes "he").
+func ParseLanguage(s string) (Language, error) {
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", &ParseError{Type: "language", Value: s, Reason: "expected a BCP 47 language tag"}
+	}
+	return Language(tag.String()), nil
+}
+
+// String returns the language tag.
+func (n Language) String() string {
+	return string(n)
+}
+
+// Tag returns the parsed language tag, or language.Und if the language is empty or invalid.
+func (n Language) Tag() language.Tag {
+	tag, err := language.Parse(string(n))
+	if err != nil {
+		return language.Und
+	}
+	return tag
+}
+
+// Base returns the primary language subtag, such as "zh" for "zh-Hant-TW".
+func (n Language) Base() string {
+	tag := n.Tag()
+	if tag == language.Und {
+		return ""
+	}
+	base, _ := tag.Base()
+	return base.String()
+}
+
+// Region returns the region of the language tag, if explicitly specified.
+func (n Language) Region() (Country, bool) {
+	region, confidence := n.Tag().Region()
+	if confidence != language.Exact {
+		return "", false
+	}
+	country, err := ParseCountry(region.String())
+	return country, err == nil
+}
+
+// MarshalJSON marshals the language as its tag.
+func (n Language) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(n))
+}
+
+// UnmarshalJSON ensures the language is a BCP 47 tag and canonicalizes it. An empty string or null is the zero language.
+func (n *Language) UnmarshalJSON(data []byte) error {
+	var s string
+	// Unmarshalling null leaves s empty.
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*n = ""
+		return nil
+	}
+	parsed, err := ParseLanguage(s)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Language) Value() (driver.Value, error) {
+	if n == "" {
+		return nil, nil
+	}
+	return string(n), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Language) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = ""
+	case string, []byte:
+		parsed, err := ParseLanguage(asString(t))
+		if err != nil {
+			return err
+		}
+		*n = parsed
+	default:
+		return fmt.Errorf("incompatible type %T for Language", value)
+	}
+	return nil
+}
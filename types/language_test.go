@@ -0,0 +1,32 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLanguageUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    Language
+		wantErr bool
+	}{
+		{`"en-us"`, "en-US", false},
+		{`"iw"`, "he", false},
+		{`""`, "", false},
+		{`null`, "", false},
+		{`"not a tag"`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			n := Language("fr")
+			err := json.Unmarshal([]byte(tt.data), &n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && n != tt.want {
+				t.Errorf("language = %q, want %q", n, tt.want)
+			}
+		})
+	}
+}
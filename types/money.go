@@ -0,0 +1,92 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCurrencyMismatch indicates an operation between amounts in different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an amount in a currency.
+// It is stored as a Postgres numeric amount column next to a currency column, and marshals to JSON as
+// {"amount": "12.30", "currency": "EUR"}.
+type Money struct {
+	Amount   Decimal  `json:"amount" db:"amount"`
+	Currency Currency `json:"currency" db:"currency"`
+}
+
+// NewMoney returns an amount in a currency.
+func NewMoney(amount Decimal, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses an amount such as "12.30" in a currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	c, err := ParseCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	a, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: a, Currency: c}, nil
+}
+
+// String returns the amount followed by the currency code.
+func (n Money) String() string {
+	return fmt.Sprintf("%s %s", n.Amount, n.Currency)
+}
+
+// IsZero reports whether the amount is 0.
+func (n Money) IsZero() bool {
+	return n.Amount.IsZero()
+}
+
+func (n Money) check(other Money) error {
+	if n.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, n.Currency, other.Currency)
+	}
+	return nil
+}
+
+// Add returns n + other, or ErrCurrencyMismatch if the currencies differ.
+func (n Money) Add(other Money) (Money, error) {
+	if err := n.check(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: n.Amount.Add(other.Amount), Currency: n.Currency}, nil
+}
+
+// Sub returns n - other, or ErrCurrencyMismatch if the currencies differ.
+func (n Money) Sub(other Money) (Money, error) {
+	if err := n.check(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: n.Amount.Sub(other.Amount), Currency: n.Currency}, nil
+}
+
+// Cmp compares the amounts, or returns ErrCurrencyMismatch if the currencies differ.
+func (n Money) Cmp(other Money) (int, error) {
+	if err := n.check(other); err != nil {
+		return 0, err
+	}
+	return n.Amount.Cmp(other.Amount), nil
+}
+
+// Mul returns the amount multiplied by a factor, such as a quantity or a tax rate.
+// The result is not rounded.
+func (n Money) Mul(factor Decimal) Money {
+	return Money{Amount: n.Amount.Mul(factor), Currency: n.Currency}
+}
+
+// Neg returns -n.
+func (n Money) Neg() Money {
+	return Money{Amount: n.Amount.Neg(), Currency: n.Currency}
+}
+
+// Round rounds the amount to the minor units of its currency (2 for EUR, 0 for JPY, 3 for KWD...).
+func (n Money) Round(mode RoundingMode) Money {
+	return Money{Amount: n.Amount.Round(n.Currency.MinorUnits(), mode), Currency: n.Currency}
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustMoney(t *testing.T, amount, currency string) Money {
+	t.Helper()
+	m, err := ParseMoney(amount, currency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := mustMoney(t, "19.99", "EUR")
+
+	sum, err := price.Add(mustMoney(t, "0.01", "EUR"))
+	if err != nil || sum.String() != "20.00 EUR" {
+		t.Errorf("Add = %v, %v, want 20.00 EUR", sum, err)
+	}
+	diff, err := price.Sub(mustMoney(t, "20", "EUR"))
+	if err != nil || diff.String() != "-0.01 EUR" {
+		t.Errorf("Sub = %v, %v, want -0.01 EUR", diff, err)
+	}
+	if cmp, err := price.Cmp(mustMoney(t, "19.990", "EUR")); err != nil || cmp != 0 {
+		t.Errorf("Cmp = %d, %v, want 0", cmp, err)
+	}
+	if got := price.Mul(mustDecimal(t, "3")).String(); got != "59.97 EUR" {
+		t.Errorf("Mul = %s, want 59.97 EUR", got)
+	}
+	if got := price.Neg().String(); got != "-19.99 EUR" {
+		t.Errorf("Neg = %s, want -19.99 EUR", got)
+	}
+
+	usd := mustMoney(t, "1", "USD")
+	if _, err := price.Add(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := price.Sub(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := price.Cmp(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		mode             RoundingMode
+		want             string
+	}{
+		{"19.99", "EUR", RoundHalfEven, "19.99 EUR"},
+		// A 20% tax on 12.345 EUR.
+		{"2.4690", "EUR", RoundHalfEven, "2.47 EUR"},
+		{"2.465", "EUR", RoundHalfEven, "2.46 EUR"},
+		{"2.465", "EUR", RoundHalfUp, "2.47 EUR"},
+		{"-2.465", "EUR", RoundHalfUp, "-2.47 EUR"},
+		{"1234.5", "JPY", RoundHalfEven, "1234 JPY"},
+		{"1234.5", "JPY", RoundUp, "1235 JPY"},
+		{"1.23456", "KWD", RoundHalfUp, "1.235 KWD"},
+		{"1.23456", "KWD", RoundDown, "1.234 KWD"},
+		{"5", "CLF", RoundHalfEven, "5.0000 CLF"},
+	}
+	for _, tt := range tests {
+		if got := mustMoney(t, tt.amount, tt.currency).Round(tt.mode).String(); got != tt.want {
+			t.Errorf("Round(%s %s, %d) = %s, want %s", tt.amount, tt.currency, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	if _, err := ParseMoney("12.30", "EURO"); err == nil {
+		t.Error("ParseMoney with an invalid currency succeeded")
+	}
+	if _, err := ParseMoney("12,30", "EUR"); err == nil {
+		t.Error("ParseMoney with an invalid amount succeeded")
+	}
+}
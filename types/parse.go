@@ -0,0 +1,109 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseMode defines how strictly dates and times are parsed.
+type ParseMode int
+
+const (
+	// ParseDefault defers to the mode configured for the type, then to DefaultParseMode.
+	ParseDefault ParseMode = iota
+	// ParseStrict only accepts 'YYYY-MM-DD' dates and 'HH:MM:SS[.fffffffff]' times.
+	ParseStrict
+	// ParseLenient also accepts 'HH:MM' times and RFC3339 timestamps as dates.
+	ParseLenient
+)
+
+var (
+	// DefaultParseMode is the package-wide parse mode used by JSON and Scan, strict by default.
+	// The GraphQL unmarshalers are always strict.
+	DefaultParseMode = ParseStrict
+	// TimeParseMode overrides DefaultParseMode for Time when not set to ParseDefault.
+	TimeParseMode = ParseDefault
+	// DateParseMode overrides DefaultParseMode for Date when not set to ParseDefault.
+	DateParseMode = ParseDefault
+)
+
+func (m ParseMode) resolve(typeMode ParseMode) ParseMode {
+	if m != ParseDefault {
+		return m
+	}
+	if typeMode != ParseDefault {
+		return typeMode
+	}
+	if DefaultParseMode != ParseDefault {
+		return DefaultParseMode
+	}
+	return ParseStrict
+}
+
+// ParseError describes a value that could not be parsed.
+type ParseError struct {
+	// The name of the type being parsed.
+	Type string
+	// The invalid value.
+	Value string
+	// Why the value is invalid.
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Type, e.Value, e.Reason)
+}
+
+const timeLayout = "15:04:05.999999999"
+
+// ParseTime parses a 'HH:MM:SS[.fffffffff]' formatted time.
+// In lenient mode, 'HH:MM' is accepted too.
+func ParseTime(s string, mode ParseMode) (Time, error) {
+	mode = mode.resolve(TimeParseMode)
+	parsed, err := time.Parse(time.TimeOnly, s)
+	if err == nil {
+		return timeOf(parsed), nil
+	}
+	if mode == ParseLenient {
+		if parsed, err := time.Parse("15:04", s); err == nil {
+			return timeOf(parsed), nil
+		}
+	}
+	expected := "expected 'HH:MM:SS[.fffffffff]'"
+	if mode == ParseLenient {
+		expected = "expected 'HH:MM:SS[.fffffffff]' or 'HH:MM'"
+	}
+	return Time{}, newParseError("time", s, expected, err)
+}
+
+// ParseDate parses a 'YYYY-MM-DD' formatted date.
+// In lenient mode, RFC3339 timestamps are accepted too and their date in their own offset is kept.
+func ParseDate(s string, mode ParseMode) (Date, error) {
+	mode = mode.resolve(DateParseMode)
+	parsed, err := time.Parse(time.DateOnly, s)
+	if err == nil {
+		return Date{Time: parsed}, nil
+	}
+	if mode == ParseLenient {
+		if parsed, err := time.Parse(time.RFC3339, s); err == nil {
+			return DateOf(parsed), nil
+		}
+	}
+	expected := "expected 'YYYY-MM-DD'"
+	if mode == ParseLenient {
+		expected = "expected 'YYYY-MM-DD' or a RFC3339 timestamp"
+	}
+	return Date{}, newParseError("date", s, expected, err)
+}
+
+func newParseError(typ, value, expected string, err error) error {
+	reason := expected
+	// Out of range errors are more useful than the expected format.
+	var parseErr *time.ParseError
+	if errors.As(err, &parseErr) && strings.HasSuffix(parseErr.Message, "out of range") {
+		reason = strings.TrimPrefix(parseErr.Message, ": ")
+	}
+	return &ParseError{Type: typ, Value: value, Reason: reason}
+}
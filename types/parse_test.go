@@ -0,0 +1,48 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(mode ParseMode) error
+		strict  bool
+		lenient bool
+	}{
+		{"full time", func(mode ParseMode) error { _, err := ParseTime("13:45:30", mode); return err }, true, true},
+		{"fractional time", func(mode ParseMode) error { _, err := ParseTime("13:45:30.123", mode); return err }, true, true},
+		{"short time", func(mode ParseMode) error { _, err := ParseTime("13:45", mode); return err }, false, true},
+		{"date", func(mode ParseMode) error { _, err := ParseDate("2024-05-06", mode); return err }, true, true},
+		{"timestamp as date", func(mode ParseMode) error { _, err := ParseDate("2024-05-06T23:30:00-02:00", mode); return err }, false, true},
+		{"out of range date", func(mode ParseMode) error { _, err := ParseDate("2024-13-06", mode); return err }, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.parse(ParseStrict); (err == nil) != tt.strict {
+				t.Errorf("strict error = %v, want accepted %v", err, tt.strict)
+			}
+			if err := tt.parse(ParseLenient); (err == nil) != tt.lenient {
+				t.Errorf("lenient error = %v, want accepted %v", err, tt.lenient)
+			}
+		})
+	}
+}
+
+func TestParseDefaultIsStrict(t *testing.T) {
+	var d Date
+	if err := json.Unmarshal([]byte(`"2024-05-06T23:30:00-02:00"`), &d); err == nil {
+		t.Errorf("unmarshalling a timestamp as a date succeeded, want an error by default")
+	}
+
+	DateParseMode = ParseLenient
+	defer func() { DateParseMode = ParseDefault }()
+	if err := json.Unmarshal([]byte(`"2024-05-06T23:30:00-02:00"`), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "2024-05-06" {
+		t.Errorf("date = %s, want the date in the offset of the timestamp", d)
+	}
+}
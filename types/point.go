@@ -0,0 +1,275 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SRIDWGS84 is the spatial reference of GPS coordinates, and the one used by GeoJSON.
+const SRIDWGS84 = 4326
+
+// earthRadius is the mean Earth radius in meters.
+const earthRadius = 6371008.8
+
+// Point is a PostGIS geographic point that is NULL when set to its zero.
+// A zero SRID is written as SRIDWGS84.
+type Point struct {
+	Lat  float64
+	Lng  float64
+	SRID int
+}
+
+// NewPoint returns a WGS84 point.
+func NewPoint(lat, lng float64) Point {
+	return Point{Lat: lat, Lng: lng, SRID: SRIDWGS84}
+}
+
+// IsZero reports whether the point is its zero value.
+func (n Point) IsZero() bool {
+	return n == Point{}
+}
+
+func (n Point) srid() int {
+	if n.SRID == 0 {
+		return SRIDWGS84
+	}
+	return n.SRID
+}
+
+// String returns the point formatted as PostGIS EWKT.
+func (n Point) String() string {
+	return fmt.Sprintf("SRID=%d;POINT(%s %s)", n.srid(),
+		strconv.FormatFloat(n.Lng, 'f', -1, 64),
+		strconv.FormatFloat(n.Lat, 'f', -1, 64))
+}
+
+// ParseEWKT parses a PostGIS EWKT point such as "SRID=4326;POINT(2.35 48.85)".
+// Without SRID, the point is assumed to be WGS84.
+func ParseEWKT(s string) (Point, error) {
+	p := Point{SRID: SRIDWGS84}
+	wkt := strings.TrimSpace(s)
+	if prefix, rest, ok := strings.Cut(wkt, ";"); ok {
+		srid, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(prefix), "SRID="))
+		if err != nil || !strings.HasPrefix(strings.ToUpper(prefix), "SRID=") {
+			return Point{}, &ParseError{Type: "point", Value: s, Reason: "invalid SRID"}
+		}
+		p.SRID, wkt = srid, rest
+	}
+	upper := strings.ToUpper(strings.TrimSpace(wkt))
+	if !strings.HasPrefix(upper, "POINT") {
+		return Point{}, &ParseError{Type: "point", Value: s, Reason: "expected a POINT"}
+	}
+	coords := strings.TrimSpace(upper[len("POINT"):])
+	if !strings.HasPrefix(coords, "(") || !strings.HasSuffix(coords, ")") {
+		return Point{}, &ParseError{Type: "point", Value: s, Reason: "expected 'POINT(lng lat)'"}
+	}
+	fields := strings.Fields(coords[1 : len(coords)-1])
+	if len(fields) != 2 {
+		return Point{}, &ParseError{Type: "point", Value: s, Reason: "only 2D points are supported"}
+	}
+	var err error
+	if p.Lng, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return Point{}, &ParseError{Type: "point", Value: s, Reason: "invalid longitude"}
+	}
+	if p.Lat, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return Point{}, &ParseError{Type: "point", Value: s, Reason: "invalid latitude"}
+	}
+	return p, nil
+}
+
+const (
+	wkbPoint     = 1
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+	ewkbSRIDFlag = 0x20000000
+)
+
+// MarshalEWKB returns the point encoded as little-endian PostGIS EWKB.
+func (n Point) MarshalEWKB() []byte {
+	b := make([]byte, 0, 25)
+	b = append(b, 1)
+	b = binary.LittleEndian.AppendUint32(b, wkbPoint|ewkbSRIDFlag)
+	b = binary.LittleEndian.AppendUint32(b, uint32(n.srid()))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(n.Lng))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(n.Lat))
+	return b
+}
+
+// ParseEWKB parses a PostGIS EWKB or WKB point. Without SRID, the point is assumed to be WGS84.
+func ParseEWKB(data []byte) (Point, error) {
+	invalid := func(reason string) error {
+		return &ParseError{Type: "point", Value: hex.EncodeToString(data), Reason: reason}
+	}
+	if len(data) < 5 {
+		return Point{}, invalid("truncated EWKB")
+	}
+	var order binary.ByteOrder
+	switch data[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return Point{}, invalid("invalid EWKB byte order")
+	}
+	typ := order.Uint32(data[1:5])
+	if typ&(ewkbZFlag|ewkbMFlag) != 0 {
+		return Point{}, invalid("only 2D points are supported")
+	}
+	if typ&^ewkbSRIDFlag != wkbPoint {
+		return Point{}, invalid("expected a POINT")
+	}
+	p, rest := Point{SRID: SRIDWGS84}, data[5:]
+	if typ&ewkbSRIDFlag != 0 {
+		if len(rest) < 4 {
+			return Point{}, invalid("truncated EWKB")
+		}
+		p.SRID, rest = int(order.Uint32(rest)), rest[4:]
+	}
+	if len(rest) != 16 {
+		return Point{}, invalid("truncated EWKB")
+	}
+	p.Lng = math.Float64frombits(order.Uint64(rest))
+	p.Lat = math.Float64frombits(order.Uint64(rest[8:]))
+	return p, nil
+}
+
+// geoJSONPoint is the GeoJSON representation of a point.
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// MarshalJSON marshals the point as a GeoJSON Point.
+func (n Point) MarshalJSON() ([]byte, error) {
+	if n.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(geoJSONPoint{Type: "Point", Coordinates: []float64{n.Lng, n.Lat}})
+}
+
+// UnmarshalJSON unmarshals the point from a GeoJSON Point.
+func (n *Point) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = Point{}
+		return nil
+	}
+	var g geoJSONPoint
+	if err := json.Unmarshal(data, &g); err != nil {
+		return err
+	}
+	if g.Type != "Point" || len(g.Coordinates) != 2 {
+		return errors.New("point must be a 2D GeoJSON Point")
+	}
+	*n = NewPoint(g.Coordinates[1], g.Coordinates[0])
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Point) Value() (driver.Value, error) {
+	if n.IsZero() {
+		return nil, nil
+	}
+	return n.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts hex encoded EWKB as returned by PostGIS in text mode, raw EWKB and EWKT.
+func (n *Point) Scan(value any) error {
+	var parsed Point
+	var err error
+	switch t := value.(type) {
+	case nil:
+		*n = Point{}
+		return nil
+	case string, []byte:
+		s := asString(t)
+		switch upper := strings.ToUpper(s); {
+		case strings.HasPrefix(upper, "SRID=") || strings.HasPrefix(upper, "POINT"):
+			parsed, err = ParseEWKT(s)
+		case len(s) > 0 && (s[0] == 0 || s[0] == 1):
+			parsed, err = ParseEWKB([]byte(s))
+		default:
+			var data []byte
+			if data, err = hex.DecodeString(s); err != nil {
+				return &ParseError{Type: "point", Value: s, Reason: "expected EWKB or EWKT"}
+			}
+			parsed, err = ParseEWKB(data)
+		}
+	default:
+		return fmt.Errorf("incompatible type %T for Point", value)
+	}
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// Distance returns the great-circle distance to other in meters, using the haversine formula.
+func (n Point) Distance(other Point) float64 {
+	lat1, lat2 := radians(n.Lat), radians(other.Lat)
+	dLat, dLng := lat2-lat1, radians(other.Lng-n.Lng)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBox is a latitude/longitude rectangle.
+// Min.Lng is greater than Max.Lng when the box crosses the antimeridian.
+type BoundingBox struct {
+	Min Point
+	Max Point
+}
+
+// BoundingBox returns the smallest box containing every point within radius meters,
+// suitable to prefilter a distance query with an index.
+func (n Point) BoundingBox(radius float64) BoundingBox {
+	angular := radius / earthRadius
+	lat := radians(n.Lat)
+	minLat, maxLat := lat-angular, lat+angular
+	minLng, maxLng := -math.Pi, math.Pi
+	if minLat > -math.Pi/2 && maxLat < math.Pi/2 {
+		dLng := math.Asin(math.Sin(angular) / math.Cos(lat))
+		minLng, maxLng = radians(n.Lng)-dLng, radians(n.Lng)+dLng
+		if minLng < -math.Pi {
+			minLng += 2 * math.Pi
+		}
+		if maxLng > math.Pi {
+			maxLng -= 2 * math.Pi
+		}
+	} else {
+		// The box contains a pole, so it spans every longitude.
+		minLat, maxLat = math.Max(minLat, -math.Pi/2), math.Min(maxLat, math.Pi/2)
+	}
+	return BoundingBox{
+		Min: Point{Lat: degrees(minLat), Lng: degrees(minLng), SRID: n.SRID},
+		Max: Point{Lat: degrees(maxLat), Lng: degrees(maxLng), SRID: n.SRID},
+	}
+}
+
+// Contains reports whether the point is within the box.
+func (b BoundingBox) Contains(p Point) bool {
+	if p.Lat < b.Min.Lat || p.Lat > b.Max.Lat {
+		return false
+	}
+	if b.Min.Lng <= b.Max.Lng {
+		return p.Lng >= b.Min.Lng && p.Lng <= b.Max.Lng
+	}
+	return p.Lng >= b.Min.Lng || p.Lng <= b.Max.Lng
+}
@@ -0,0 +1,614 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ of a recurrence rule.
+type Frequency int
+
+const (
+	// Yearly repeats every year.
+	Yearly Frequency = iota + 1
+	// Monthly repeats every month.
+	Monthly
+	// Weekly repeats every week.
+	Weekly
+	// Daily repeats every day.
+	Daily
+	// Hourly repeats every hour.
+	Hourly
+	// Minutely repeats every minute.
+	Minutely
+	// Secondly repeats every second.
+	Secondly
+)
+
+var frequencyNames = []string{Yearly: "YEARLY", Monthly: "MONTHLY", Weekly: "WEEKLY", Daily: "DAILY", Hourly: "HOURLY", Minutely: "MINUTELY", Secondly: "SECONDLY"}
+
+// String returns the RFC 5545 name of the frequency.
+func (f Frequency) String() string {
+	if f < Yearly || f > Secondly {
+		return ""
+	}
+	return frequencyNames[f]
+}
+
+var weekdayNames = []string{time.Sunday: "SU", time.Monday: "MO", time.Tuesday: "TU", time.Wednesday: "WE", time.Thursday: "TH", time.Friday: "FR", time.Saturday: "SA"}
+
+// WeekdayNum is a BYDAY value such as "MO", "1MO" (the first Monday) or "-1FR" (the last Friday).
+type WeekdayNum struct {
+	// The nth occurrence within the month or the year, 0 for every occurrence.
+	N       int
+	Weekday time.Weekday
+}
+
+// String returns the RFC 5545 form of the weekday.
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Weekday]
+}
+
+// RRule is an RFC 5545 recurrence rule that is NULL when its frequency is not set.
+type RRule struct {
+	Freq Frequency
+	// Interval between periods, 1 if not set.
+	Interval int
+	// Count limits the number of occurrences, 0 for no limit.
+	Count int
+	// Until is the last possible occurrence as a UTC instant, or zero.
+	Until time.Time
+	// UntilDate is the last possible occurrence day in the recurrence time zone, or zero.
+	UntilDate  Date
+	ByMonth    []int
+	ByWeekNo   []int
+	ByYearDay  []int
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+	ByHour     []int
+	ByMinute   []int
+	BySecond   []int
+	BySetPos   []int
+	// WeekStart is the first day of the week, time.Monday when parsed without WKST.
+	WeekStart time.Weekday
+}
+
+const rruleUntilLayout = "20060102T150405Z"
+
+// ParseRRule parses an RFC 5545 RRULE value, with or without the "RRULE:" prefix.
+func ParseRRule(s string) (RRule, error) {
+	r := RRule{WeekStart: time.Monday}
+	value := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return RRule{}, &ParseError{Type: "rrule", Value: s, Reason: fmt.Sprintf("expected NAME=VALUE, got %q", part)}
+		}
+		if seen[name] {
+			return RRule{}, &ParseError{Type: "rrule", Value: s, Reason: fmt.Sprintf("duplicate %s", name)}
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			if r.Freq = Frequency(slices.Index(frequencyNames, val)); r.Freq <= 0 {
+				err = errors.New("unknown frequency")
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+			if err == nil && r.Count < 1 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			if len(val) == len("20060102") {
+				var until time.Time
+				until, err = time.Parse("20060102", val)
+				r.UntilDate = Date{Time: until}
+			} else if strings.HasSuffix(val, "Z") {
+				r.Until, err = time.Parse(rruleUntilLayout, val)
+			} else {
+				err = errors.New("date-time must be in UTC")
+			}
+		case "BYMONTH":
+			r.ByMonth, err = parseRRuleInts(val, 1, 12, false)
+		case "BYWEEKNO":
+			r.ByWeekNo, err = parseRRuleInts(val, 1, 53, true)
+		case "BYYEARDAY":
+			r.ByYearDay, err = parseRRuleInts(val, 1, 366, true)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseRRuleInts(val, 1, 31, true)
+		case "BYDAY":
+			r.ByDay, err = parseRRuleWeekdays(val)
+		case "BYHOUR":
+			r.ByHour, err = parseRRuleInts(val, 0, 23, false)
+		case "BYMINUTE":
+			r.ByMinute, err = parseRRuleInts(val, 0, 59, false)
+		case "BYSECOND":
+			r.BySecond, err = parseRRuleInts(val, 0, 59, false)
+		case "BYSETPOS":
+			r.BySetPos, err = parseRRuleInts(val, 1, 366, true)
+		case "WKST":
+			if w := slices.Index(weekdayNames, val); w >= 0 {
+				r.WeekStart = time.Weekday(w)
+			} else {
+				err = errors.New("unknown weekday")
+			}
+		default:
+			err = errors.New("unknown rule part")
+		}
+		if err != nil {
+			return RRule{}, &ParseError{Type: "rrule", Value: s, Reason: fmt.Sprintf("invalid %s %q: %v", name, val, err)}
+		}
+	}
+
+	switch {
+	case r.Freq == 0:
+		return RRule{}, &ParseError{Type: "rrule", Value: s, Reason: "missing FREQ"}
+	case r.Count > 0 && (!r.Until.IsZero() || !r.UntilDate.IsZero()):
+		return RRule{}, &ParseError{Type: "rrule", Value: s, Reason: "COUNT and UNTIL are mutually exclusive"}
+	case len(r.ByWeekNo) > 0 && r.Freq != Yearly:
+		return RRule{}, &ParseError{Type: "rrule", Value: s, Reason: "BYWEEKNO is only valid with FREQ=YEARLY"}
+	}
+	return r, nil
+}
+
+func parseRRuleInts(s string, low, high int, signed bool) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		if abs := max(v, -v); abs < low || abs > high || (!signed && v < 0) {
+			return nil, fmt.Errorf("%d is out of range", v)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func parseRRuleWeekdays(s string) ([]WeekdayNum, error) {
+	var values []WeekdayNum
+	for _, part := range strings.Split(s, ",") {
+		if len(part) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", part)
+		}
+		w := slices.Index(weekdayNames, part[len(part)-2:])
+		if w < 0 {
+			return nil, fmt.Errorf("invalid weekday %q", part)
+		}
+		value := WeekdayNum{Weekday: time.Weekday(w)}
+		if prefix := part[:len(part)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n > 53 || n < -53 {
+				return nil, fmt.Errorf("invalid weekday %q", part)
+			}
+			value.N = n
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// String returns the rule as an RFC 5545 RRULE value, without the "RRULE:" prefix.
+func (r RRule) String() string {
+	if r.Freq == 0 {
+		return ""
+	}
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(rruleUntilLayout))
+	} else if !r.UntilDate.IsZero() {
+		parts = append(parts, "UNTIL="+r.UntilDate.Format("20060102"))
+	}
+	join := func(name string, values []int) {
+		if len(values) > 0 {
+			s := make([]string, len(values))
+			for i, v := range values {
+				s[i] = strconv.Itoa(v)
+			}
+			parts = append(parts, name+"="+strings.Join(s, ","))
+		}
+	}
+	join("BYMONTH", r.ByMonth)
+	join("BYWEEKNO", r.ByWeekNo)
+	join("BYYEARDAY", r.ByYearDay)
+	join("BYMONTHDAY", r.ByMonthDay)
+	if len(r.ByDay) > 0 {
+		s := make([]string, len(r.ByDay))
+		for i, w := range r.ByDay {
+			s[i] = w.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(s, ","))
+	}
+	join("BYHOUR", r.ByHour)
+	join("BYMINUTE", r.ByMinute)
+	join("BYSECOND", r.BySecond)
+	join("BYSETPOS", r.BySetPos)
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// MarshalJSON marshals the rule as an RRULE string.
+func (r RRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON unmarshals the rule from an RRULE string.
+func (r *RRule) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*r = RRule{}
+		return nil
+	}
+	parsed, err := ParseRRule(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (r RRule) Value() (driver.Value, error) {
+	if r.Freq == 0 {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *RRule) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*r = RRule{}
+	case string, []byte:
+		parsed, err := ParseRRule(asString(t))
+		if err != nil {
+			return err
+		}
+		*r = parsed
+	default:
+		return fmt.Errorf("incompatible type %T for RRule", value)
+	}
+	return nil
+}
+
+// DefaultMaxRecurrenceIterations is the default number of consecutive periods without any occurrence
+// after which an iteration gives up, so that rules such as "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30" cannot loop forever.
+const DefaultMaxRecurrenceIterations = 10000
+
+// ErrRecurrenceIterations indicates that an iteration gave up looking for the next occurrence.
+var ErrRecurrenceIterations = errors.New("too many iterations without occurrence")
+
+// Recurrence expands a rule starting on a date at a time of day in a time zone.
+// Wall clock times skipped by a DST transition are shifted forward, ambiguous ones resolve to the earlier instant.
+type Recurrence struct {
+	Rule      RRule
+	Start     Date
+	StartTime Time
+	TimeZone  TimeZone
+	// ExDates are excluded occurrences (EXDATE). As defined by RFC 5545, they still count towards the rule COUNT.
+	ExDates []time.Time
+	// MaxIterations overrides DefaultMaxRecurrenceIterations when set.
+	MaxIterations int
+}
+
+// Iterator returns an iterator over the occurrences of the recurrence.
+// A rule without frequency, such as a NULL one, has no occurrences.
+func (r Recurrence) Iterator() *RecurrenceIterator {
+	rule := r.Rule
+	if rule.Freq < Yearly || rule.Freq > Secondly {
+		return &RecurrenceIterator{done: true}
+	}
+	if rule.Interval < 1 {
+		rule.Interval = 1
+	}
+	start := r.Start.civil().Add(time.Duration(r.StartTime.Hour())*time.Hour +
+		time.Duration(r.StartTime.Minute())*time.Minute +
+		time.Duration(r.StartTime.Second())*time.Second)
+
+	// Default expansions from the start, as defined by RFC 5545.
+	switch rule.Freq {
+	case Yearly, Monthly:
+		if len(rule.ByMonthDay) == 0 && len(rule.ByYearDay) == 0 && len(rule.ByDay) == 0 && len(rule.ByWeekNo) == 0 {
+			rule.ByMonthDay = []int{start.Day()}
+			if rule.Freq == Yearly && len(rule.ByMonth) == 0 {
+				rule.ByMonth = []int{int(start.Month())}
+			}
+		}
+	case Weekly:
+		if len(rule.ByDay) == 0 {
+			rule.ByDay = []WeekdayNum{{Weekday: start.Weekday()}}
+		}
+	}
+
+	maxIterations := r.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxRecurrenceIterations
+	}
+	return &RecurrenceIterator{
+		rule:          rule,
+		start:         start,
+		tz:            r.TimeZone,
+		exDates:       r.ExDates,
+		maxIterations: maxIterations,
+	}
+}
+
+// Between returns the occurrences in [after, before), at most limit of them if limit is positive.
+func (r Recurrence) Between(after, before time.Time, limit int) ([]time.Time, error) {
+	var occurrences []time.Time
+	it := r.Iterator()
+	for {
+		t, ok := it.Next()
+		if !ok || !t.Before(before) {
+			return occurrences, it.Err()
+		}
+		if t.Before(after) {
+			continue
+		}
+		occurrences = append(occurrences, t)
+		if limit > 0 && len(occurrences) >= limit {
+			return occurrences, nil
+		}
+	}
+}
+
+// RecurrenceIterator iterates over the occurrences of a recurrence in chronological order.
+type RecurrenceIterator struct {
+	rule          RRule
+	start         time.Time
+	tz            TimeZone
+	exDates       []time.Time
+	maxIterations int
+
+	period int
+	buffer []time.Time
+	count  int
+	done   bool
+	err    error
+}
+
+// Next returns the next occurrence, or false when the iteration is over.
+func (it *RecurrenceIterator) Next() (time.Time, bool) {
+	for iterations := 0; !it.done; {
+		for len(it.buffer) > 0 {
+			wall := it.buffer[0]
+			it.buffer = it.buffer[1:]
+			if !it.rule.UntilDate.IsZero() && DateOf(wall).AfterDate(it.rule.UntilDate) {
+				it.done = true
+				return time.Time{}, false
+			}
+			instant := DateOf(wall).At(NewTime(wall.Hour(), wall.Minute(), wall.Second(), 0), it.tz)
+			if !it.rule.Until.IsZero() && instant.After(it.rule.Until) {
+				it.done = true
+				return time.Time{}, false
+			}
+			it.count++
+			if it.rule.Count > 0 && it.count > it.rule.Count {
+				it.done = true
+				return time.Time{}, false
+			}
+			if slices.ContainsFunc(it.exDates, instant.Equal) {
+				continue
+			}
+			return instant, true
+		}
+
+		if iterations++; iterations > it.maxIterations {
+			it.done, it.err = true, ErrRecurrenceIterations
+			break
+		}
+		for _, wall := range it.expand(it.period) {
+			if !wall.Before(it.start) {
+				it.buffer = append(it.buffer, wall)
+			}
+		}
+		it.advance()
+	}
+	return time.Time{}, false
+}
+
+// subDailyUnits are the durations of the periods of the sub-daily frequencies.
+var subDailyUnits = map[Frequency]time.Duration{Hourly: time.Hour, Minutely: time.Minute, Secondly: time.Second}
+
+// advance moves to the next period. Sub-daily periods excluded by the day, BYHOUR or BYMINUTE filters are skipped
+// up to the next day, hour or minute, so that rules such as "FREQ=SECONDLY;BYHOUR=9" do not exhaust
+// the iterations one second at a time.
+func (it *RecurrenceIterator) advance() {
+	r := it.rule
+	unit, ok := subDailyUnits[r.Freq]
+	if !ok {
+		it.period++
+		return
+	}
+	step := time.Duration(r.Interval) * unit
+	current := it.start.Add(time.Duration(it.period) * step)
+	var next time.Time
+	switch {
+	case !it.matchDay(DateOf(current).civil()):
+		next = DateOf(current).civil().AddDate(0, 0, 1)
+	case r.Freq > Hourly && len(r.ByHour) > 0 && !slices.Contains(r.ByHour, current.Hour()):
+		next = current.Truncate(time.Hour).Add(time.Hour)
+	case r.Freq > Minutely && len(r.ByMinute) > 0 && !slices.Contains(r.ByMinute, current.Minute()):
+		next = current.Truncate(time.Minute).Add(time.Minute)
+	default:
+		it.period++
+		return
+	}
+	// The first period at or after next.
+	it.period += max(1, int((next.Sub(current)+step-1)/step))
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RecurrenceIterator) Err() error {
+	return it.err
+}
+
+// expand returns the sorted wall clock times of the nth period.
+func (it *RecurrenceIterator) expand(n int) []time.Time {
+	r, start := it.rule, it.start
+	step := n * r.Interval
+
+	var days []time.Time
+	var hour, minute, second = -1, -1, -1
+	switch r.Freq {
+	case Yearly:
+		first := time.Date(start.Year()+step, 1, 1, 0, 0, 0, 0, time.UTC)
+		days = dayRange(first, first.AddDate(1, 0, 0))
+	case Monthly:
+		first := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		days = dayRange(first, first.AddDate(0, 1, 0))
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		first := DateOf(start).civil().AddDate(0, 0, 7*step-offset)
+		days = dayRange(first, first.AddDate(0, 0, 7))
+	case Daily:
+		days = []time.Time{DateOf(start).civil().AddDate(0, 0, step)}
+	default:
+		current := start.Add(time.Duration(step) * subDailyUnits[r.Freq])
+		days = []time.Time{DateOf(current).civil()}
+		hour = current.Hour()
+		if r.Freq >= Minutely {
+			minute = current.Minute()
+		}
+		if r.Freq == Secondly {
+			second = current.Second()
+		}
+	}
+
+	days = slices.DeleteFunc(days, func(d time.Time) bool { return !it.matchDay(d) })
+	hours := clockValues(hour, r.ByHour, start.Hour())
+	minutes := clockValues(minute, r.ByMinute, start.Minute())
+	seconds := clockValues(second, r.BySecond, start.Second())
+
+	var walls []time.Time
+	for _, d := range days {
+		for _, h := range hours {
+			for _, m := range minutes {
+				for _, s := range seconds {
+					walls = append(walls, d.Add(time.Duration(h)*time.Hour+time.Duration(m)*time.Minute+time.Duration(s)*time.Second))
+				}
+			}
+		}
+	}
+
+	if len(r.BySetPos) == 0 {
+		return walls
+	}
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		if pos > 0 && pos <= len(walls) {
+			selected = append(selected, walls[pos-1])
+		} else if pos < 0 && -pos <= len(walls) {
+			selected = append(selected, walls[len(walls)+pos])
+		}
+	}
+	slices.SortFunc(selected, time.Time.Compare)
+	return slices.CompactFunc(selected, time.Time.Equal)
+}
+
+// clockValues returns the clock values of a period: the period value if set and allowed, the BYxxx values or the start value.
+func clockValues(period int, by []int, start int) []int {
+	if period >= 0 {
+		if len(by) > 0 && !slices.Contains(by, period) {
+			return nil
+		}
+		return []int{period}
+	}
+	if len(by) == 0 {
+		return []int{start}
+	}
+	values := slices.Clone(by)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+func dayRange(from, to time.Time) []time.Time {
+	var days []time.Time
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+func (it *RecurrenceIterator) matchDay(d time.Time) bool {
+	r := it.rule
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(d.Month())) {
+		return false
+	}
+	if len(r.ByWeekNo) > 0 {
+		year, week := d.ISOWeek()
+		_, weeks := time.Date(year, 12, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+		if !slices.Contains(r.ByWeekNo, week) && !slices.Contains(r.ByWeekNo, week-weeks-1) {
+			return false
+		}
+	}
+	if len(r.ByYearDay) > 0 {
+		days := time.Date(d.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		if !slices.Contains(r.ByYearDay, d.YearDay()) && !slices.Contains(r.ByYearDay, d.YearDay()-days-1) {
+			return false
+		}
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := daysIn(d.Year(), d.Month())
+		if !slices.Contains(r.ByMonthDay, d.Day()) && !slices.Contains(r.ByMonthDay, d.Day()-days-1) {
+			return false
+		}
+	}
+	if len(r.ByDay) > 0 {
+		return slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return it.matchWeekday(d, w) })
+	}
+	return true
+}
+
+// matchWeekday matches a BYDAY value, the nth weekday being within the month for monthly rules
+// and yearly rules with BYMONTH, within the year for other yearly rules.
+func (it *RecurrenceIterator) matchWeekday(d time.Time, w WeekdayNum) bool {
+	if d.Weekday() != w.Weekday {
+		return false
+	}
+	if w.N == 0 {
+		return true
+	}
+	var index, after int
+	switch {
+	case it.rule.Freq == Monthly || (it.rule.Freq == Yearly && len(it.rule.ByMonth) > 0):
+		index, after = (d.Day()-1)/7+1, (daysIn(d.Year(), d.Month())-d.Day())/7
+	case it.rule.Freq == Yearly:
+		days := time.Date(d.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		index, after = (d.YearDay()-1)/7+1, (days-d.YearDay())/7
+	default:
+		return true
+	}
+	// -1 is the last occurrence, which is the index+after th one.
+	return w.N == index || w.N == -after-1
+}
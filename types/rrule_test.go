@@ -0,0 +1,75 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustParseRRule(t *testing.T, s string) RRule {
+	t.Helper()
+	rule, err := ParseRRule(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
+
+func TestRecurrenceZeroRule(t *testing.T) {
+	var rule RRule
+	if err := rule.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	r := Recurrence{Rule: rule, Start: NewDate(2024, 1, 1), TimeZone: TimeZone{Location: *time.UTC}}
+	occurrences, err := r.Between(time.Time{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil || len(occurrences) != 0 {
+		t.Errorf("Between = %v, %v, want no occurrence", occurrences, err)
+	}
+}
+
+func TestRecurrenceSubDailySkipsExcludedPeriods(t *testing.T) {
+	r := Recurrence{
+		Rule:      mustParseRRule(t, "FREQ=SECONDLY;BYHOUR=9;COUNT=2"),
+		Start:     NewDate(2024, 1, 1),
+		StartTime: NewTime(10, 0, 0, 0),
+		TimeZone:  TimeZone{Location: *time.UTC},
+	}
+	occurrences, err := r.Between(time.Time{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 9, 0, 1, 0, time.UTC),
+	}
+	if len(occurrences) != len(want) || !occurrences[0].Equal(want[0]) || !occurrences[1].Equal(want[1]) {
+		t.Errorf("Between = %v, want %v", occurrences, want)
+	}
+}
+
+func TestRecurrenceIterationsBudget(t *testing.T) {
+	r := Recurrence{
+		Rule:     mustParseRRule(t, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30"),
+		Start:    NewDate(2024, 1, 1),
+		TimeZone: TimeZone{Location: *time.UTC},
+	}
+	if _, err := r.Between(time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), 0); !errors.Is(err, ErrRecurrenceIterations) {
+		t.Errorf("Between error = %v, want %v", err, ErrRecurrenceIterations)
+	}
+}
+
+func TestRecurrenceExDatesCount(t *testing.T) {
+	r := Recurrence{
+		Rule:     mustParseRRule(t, "FREQ=DAILY;COUNT=3"),
+		Start:    NewDate(2024, 1, 1),
+		TimeZone: TimeZone{Location: *time.UTC},
+		ExDates:  []time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	occurrences, err := r.Between(time.Time{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 2 {
+		t.Errorf("Between = %v, want the 1st and 3rd of January", occurrences)
+	}
+}
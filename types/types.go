@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -14,7 +15,7 @@ type Time struct {
 
 // String returns the time as a string formatted as a time.
 func (n Time) String() string {
-	return n.Time.Format(time.TimeOnly)
+	return n.Time.Format(timeLayout)
 }
 
 // MarshalJSON marshals the time as a string formatted as a time.
@@ -28,16 +29,11 @@ func (n *Time) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &t); err != nil {
 		return err
 	}
-	parsed, err := time.Parse(time.TimeOnly, t)
+	parsed, err := ParseTime(t, ParseDefault)
 	if err != nil {
-		// Just in case, we also try to parse hh:mm
-		if parsed, err := time.Parse("15:04", t); err == nil {
-			*n = Time{Time: parsed}
-			return nil
-		}
 		return err
 	}
-	*n = Time{Time: parsed}
+	*n = parsed
 	return nil
 }
 
@@ -51,19 +47,16 @@ func (n *Time) Scan(value any) error {
 	switch t := value.(type) {
 	case nil:
 		*n = Time{Time: time.Time{}}
-	case string:
-		parsed, err := time.Parse(time.TimeOnly, t)
+	case string, []byte:
+		parsed, err := ParseTime(asString(t), ParseDefault)
 		if err != nil {
-			// Just in case, we also try to parse hh:mm
-			if parsed, err := time.Parse("15:04", t); err == nil {
-				*n = Time{Time: parsed}
-				return nil
-			}
 			return err
 		}
-		*n = Time{Time: parsed}
+		*n = parsed
+	case time.Time:
+		*n = timeOf(t)
 	default:
-		return errors.New("incompatible type for Time")
+		return fmt.Errorf("incompatible type %T for Time", value)
 	}
 	return nil
 }
@@ -89,16 +82,11 @@ func (n *Date) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &t); err != nil {
 		return err
 	}
-	parsed, err := time.Parse(time.DateOnly, t)
+	parsed, err := ParseDate(t, ParseDefault)
 	if err != nil {
-		// Just in case, we also try to parse RFC3339 date
-		if parsed, err := time.Parse(time.RFC3339, t); err == nil {
-			*n = Date{Time: parsed}
-			return nil
-		}
 		return err
 	}
-	*n = Date{Time: parsed}
+	*n = parsed
 	return nil
 }
 
@@ -112,19 +100,16 @@ func (n *Date) Scan(value any) error {
 	switch t := value.(type) {
 	case nil:
 		*n = Date{Time: time.Time{}}
-	case string:
-		parsed, err := time.Parse(time.DateOnly, t)
+	case string, []byte:
+		parsed, err := ParseDate(asString(t), ParseDefault)
 		if err != nil {
-			// Just in case, we also try to parse RFC3339 date
-			if parsed, err := time.Parse(time.RFC3339, t); err == nil {
-				*n = Date{Time: parsed}
-				return nil
-			}
 			return err
 		}
-		*n = Date{Time: parsed}
+		*n = parsed
+	case time.Time:
+		*n = DateOf(t)
 	default:
-		return errors.New("incompatible type for Date")
+		return fmt.Errorf("incompatible type %T for Date", value)
 	}
 	return nil
 }
@@ -179,3 +164,10 @@ func (n *TimeZone) Scan(value any) error {
 	}
 	return nil
 }
+
+func asString(value any) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value.(string)
+}
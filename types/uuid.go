@@ -0,0 +1,101 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UUID is a Postgres uuid that is NULL when set to its zero.
+type UUID struct {
+	uuid.UUID
+}
+
+// NewUUIDv4 returns a random UUID.
+func NewUUIDv4() UUID {
+	return UUID{UUID: uuid.New()}
+}
+
+// NewUUIDv7 returns a time-ordered UUID, better suited than v4 for primary keys.
+func NewUUIDv7() UUID {
+	return UUID{UUID: uuid.Must(uuid.NewV7())}
+}
+
+// ParseUUID parses a UUID in its canonical or braced/urn forms.
+func ParseUUID(s string) (UUID, error) {
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return UUID{}, &ParseError{Type: "uuid", Value: s, Reason: err.Error()}
+	}
+	return UUID{UUID: parsed}, nil
+}
+
+// IsZero reports whether the UUID is the nil UUID.
+func (n UUID) IsZero() bool {
+	return n.UUID == uuid.Nil
+}
+
+// MarshalJSON marshals the UUID as a string, or null when zero.
+func (n UUID) MarshalJSON() ([]byte, error) {
+	if n.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String())
+}
+
+// UnmarshalJSON unmarshals the UUID from a string.
+func (n *UUID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = UUID{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseUUID(s)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n UUID) Value() (driver.Value, error) {
+	if n.IsZero() {
+		return nil, nil
+	}
+	return n.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts the string form as well as the 16-byte binary form.
+func (n *UUID) Scan(value any) error {
+	switch t := value.(type) {
+	case nil:
+		*n = UUID{}
+	case []byte:
+		if len(t) == 16 {
+			copy(n.UUID[:], t)
+			return nil
+		}
+		return n.Scan(string(t))
+	case string:
+		if t == "" {
+			*n = UUID{}
+			return nil
+		}
+		parsed, err := ParseUUID(t)
+		if err != nil {
+			return err
+		}
+		*n = parsed
+	default:
+		return fmt.Errorf("incompatible type %T for UUID", value)
+	}
+	return nil
+}